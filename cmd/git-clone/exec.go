@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/internal/ui"
+	"github.com/chetanr25/mass-git-cloner/internal/workspace"
+)
+
+// runExec implements `gclone exec [flags] -- <command>`.
+func runExec(args []string) {
+	cfg := config.DefaultConfig()
+
+	fs := flag.NewFlagSet("exec", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory containing the cloned repositories")
+	jobs := fs.Int("j", cfg.Concurrency, "number of repositories to run in parallel")
+	timeout := fs.Duration("timeout", cfg.CloneTimeout, "per-repository timeout")
+	fromManifest := fs.Bool("manifest", false, "run only in repositories recorded in the manifest instead of scanning the directory")
+	aggregate := fs.Bool("aggregate", false, "print each repository's output as one block instead of prefixed lines")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gclone exec [flags] -- <command> [args...]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ui.ConfigureColor()
+
+	command := fs.Args()
+	if len(command) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	paths, err := execPaths(*dir, *fromManifest)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	if len(paths) == 0 {
+		ui.DisplayInfo(fmt.Sprintf("No git repositories found under %s", *dir))
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	results := workspace.Exec(ctx, paths, workspace.ExecOptions{
+		Command:     command,
+		Root:        *dir,
+		Concurrency: *jobs,
+		Timeout:     *timeout,
+		Aggregate:   *aggregate,
+		Out:         os.Stdout,
+	})
+
+	failed := 0
+	fmt.Println()
+	for _, result := range results {
+		if result.Passed() {
+			continue
+		}
+		failed++
+		rel, _ := filepath.Rel(*dir, result.Path)
+		ui.DisplayError(fmt.Errorf("%s: %v", rel, result.Err))
+	}
+
+	ui.DisplaySuccess(fmt.Sprintf("%d passed, %d failed (%d repositories)", len(results)-failed, failed, len(results)))
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func execPaths(dir string, fromManifest bool) ([]string, error) {
+	if fromManifest {
+		man, err := manifest.Load(dir)
+		if err != nil {
+			return nil, err
+		}
+		return man.Paths(), nil
+	}
+
+	paths, err := workspace.Discover(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return paths, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/ui"
+	"github.com/chetanr25/mass-git-cloner/internal/workspace"
+)
+
+const defaultLockfile = "gclone.lock"
+
+// runLock implements `gclone lock [dir]`.
+func runLock(args []string) {
+	fs := flag.NewFlagSet("lock", flag.ExitOnError)
+	output := fs.String("o", "", "lockfile to write (default <dir>/"+defaultLockfile+")")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gclone lock [flags] [dir]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ui.ConfigureColor()
+
+	root := "."
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+	if *output == "" {
+		*output = filepath.Join(root, defaultLockfile)
+	}
+
+	lf, skipped, err := workspace.Lock(context.Background(), config.DefaultConfig(), root)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	for _, err := range skipped {
+		ui.DisplayError(fmt.Errorf("skipping %v", err))
+	}
+
+	if err := workspace.WriteLockfile(*output, lf); err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	ui.DisplaySuccess(fmt.Sprintf("Pinned %d repositories in %s", len(lf.Repositories), *output))
+}
+
+// runRestore implements `gclone restore lockfile`.
+func runRestore(args []string) {
+	cfg := config.DefaultConfig()
+
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory to restore the workspace into")
+	jobs := fs.Int("j", cfg.Concurrency, "number of repositories to restore in parallel")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gclone restore [flags] lockfile\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ui.ConfigureColor()
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	lf, err := workspace.ReadLockfile(fs.Arg(0))
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	cfg.Concurrency = *jobs
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	ui.DisplayInfo(fmt.Sprintf("Restoring %d repositories into %s", len(lf.Repositories), *dir))
+	results := workspace.Restore(ctx, cfg, *dir, lf)
+
+	failed, unreachable := 0, 0
+	for _, result := range results {
+		switch {
+		case result.Unreachable:
+			unreachable++
+			ui.DisplayError(fmt.Errorf("%s: %v", result.Entry.Path, result.Err))
+		case result.Err != nil:
+			failed++
+			ui.DisplayError(fmt.Errorf("%s: %v", result.Entry.Path, result.Err))
+		case result.Cloned:
+			fmt.Printf("cloned   %s @ %s\n", result.Entry.Path, workspace.ShortSHA(result.Entry.Commit))
+		default:
+			fmt.Printf("checkout %s @ %s\n", result.Entry.Path, workspace.ShortSHA(result.Entry.Commit))
+		}
+	}
+
+	fmt.Println()
+	ui.DisplaySuccess(fmt.Sprintf("%d restored, %d failed, %d unreachable", len(results)-failed-unreachable, failed, unreachable))
+
+	if failed+unreachable > 0 {
+		os.Exit(1)
+	}
+}
@@ -1,96 +1,369 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/chetanr25/mass-git-cloner/internal/cloner"
 	"github.com/chetanr25/mass-git-cloner/internal/config"
 	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
 	"github.com/chetanr25/mass-git-cloner/internal/ui"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
 )
 
+type options struct {
+	output        string
+	filter        string
+	dir           string
+	layout        string
+	previewLayout bool
+	dryRun        bool
+	update        bool
+	diskCheck     string
+	diskMargin    float64
+	backend       string
+	depth         int
+	branch        string
+	wikis         bool
+	retries       int
+	concurrency   int
+	retryBackoff  time.Duration
+	source        string
+	ownerFlags    []string
+	owners        []string
+}
+
+func parseFlags() *options {
+	opts := &options{}
+
+	flag.StringVar(&opts.output, "output", string(ui.OutputAuto), "output mode: auto, tui, plain or json")
+	flag.StringVar(&opts.filter, "filter", "all", "non-interactive filter: all, sources or forks")
+	flag.StringVar(&opts.dir, "dir", ".", "base directory to clone into")
+	flag.StringVar(&opts.layout, "layout", layout.Default, "directory layout: default, ghq, owner, language or a template like '{{.Owner}}/{{.Name}}'")
+	flag.BoolVar(&opts.previewLayout, "preview-layout", false, "print the directory tree the layout would produce and exit")
+	flag.BoolVar(&opts.dryRun, "dry-run", false, "print what would be cloned, updated or skipped and exit")
+	flag.BoolVar(&opts.update, "update", false, "pull existing working copies instead of skipping them")
+	flag.StringVar(&opts.diskCheck, "disk-check", "abort", "when projected size exceeds free space: abort, warn or off")
+	flag.Float64Var(&opts.diskMargin, "disk-margin", 20, "extra free space to require, as a percentage of the projected size")
+	flag.StringVar(&opts.backend, "backend", "exec", "clone backend: exec (git binary) or go-git (no git required)")
+	flag.IntVar(&opts.depth, "depth", 0, "create shallow clones with this many commits (0 clones full history)")
+	flag.StringVar(&opts.branch, "branch", "", "branch to check out instead of each repository's default branch")
+	flag.BoolVar(&opts.wikis, "wikis", false, "also clone each repository's wiki into <repo>.wiki when it has one")
+	flag.IntVar(&opts.retries, "retries", 3, "attempts per repository when a clone fails with a transient network error")
+	flag.DurationVar(&opts.retryBackoff, "retry-backoff", 2*time.Second, "delay before the first retry; doubles after each attempt")
+	flag.IntVar(&opts.concurrency, "concurrency", config.DefaultConfig().Concurrency, "number of repositories to clone in parallel")
+	flag.IntVar(&opts.concurrency, "j", config.DefaultConfig().Concurrency, "shorthand for --concurrency")
+	flag.StringVar(&opts.source, "source", github.SourceRepos, "what to clone for the owner: repos (their repositories), starred (repositories they starred), gists, team:<slug> or topic:<name> (an organization's team or topic), or search:<query> (a GitHub repository search)")
+	flag.Func("owner", "user or organization to clone; repeat or comma-separate for several (default $GCLONE_OWNERS)", func(value string) error {
+		opts.ownerFlags = append(opts.ownerFlags, value)
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: gclone [flags] [owner...]\n       gclone plan [flags] owner\n       gclone status [flags] [dir]\n       gclone exec [flags] -- <command>\n       gclone prune [flags] owner\n       gclone lock [flags] [dir]\n       gclone restore [flags] lockfile\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "plan" {
+		opts.dryRun = true
+		args = args[1:]
+	}
+	flag.CommandLine.Parse(args)
+
+	// Starred repositories and search results can come from many owners, so
+	// group them by owner unless a layout was chosen explicitly.
+	layoutSet := false
+	flag.Visit(func(f *flag.Flag) {
+		layoutSet = layoutSet || f.Name == "layout"
+	})
+	isSearch := strings.HasPrefix(opts.source, github.SourceSearch+":")
+	if (opts.source == github.SourceStarred || isSearch) && !layoutSet {
+		opts.layout = "owner"
+	}
+
+	opts.owners = config.ParseOwners(append(flag.Args(), opts.ownerFlags...)...)
+	return opts
+}
+
 func main() {
-	ui.DisplayWelcome()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "status":
+			runStatus(os.Args[2:])
+			return
+		case "exec":
+			runExec(os.Args[2:])
+			return
+		case "prune":
+			runPrune(os.Args[2:])
+			return
+		case "lock":
+			runLock(os.Args[2:])
+			return
+		case "restore":
+			runRestore(os.Args[2:])
+			return
+		}
+	}
+
+	opts := parseFlags()
+
+	ui.ConfigureColor()
+
+	mode, err := ui.ResolveOutputMode(opts.output)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(2)
+	}
 
 	cfg := config.DefaultConfig()
+	if len(opts.owners) == 0 {
+		opts.owners = cfg.Owners
+	}
+	cfg.BaseDir = opts.dir
+	cfg.Layout = opts.layout
+	cfg.UpdateExisting = opts.update
+	cfg.DiskCheck = opts.diskCheck
+	cfg.DiskSpaceMargin = opts.diskMargin / 100
+	cfg.Backend = opts.backend
+	cfg.CloneDepth = opts.depth
+	cfg.CloneBranch = opts.branch
+	cfg.CloneWikis = opts.wikis
+	cfg.MaxAttempts = opts.retries
+	cfg.RetryBackoff = opts.retryBackoff
+	cfg.Concurrency = opts.concurrency
+
+	if cfg.Concurrency < 1 {
+		ui.DisplayError(fmt.Errorf("--concurrency must be at least 1, got %d", cfg.Concurrency))
+		os.Exit(2)
+	}
+
+	switch cfg.DiskCheck {
+	case "abort", "warn", "off":
+	default:
+		ui.DisplayError(fmt.Errorf("unknown --disk-check %q (expected abort, warn or off)", cfg.DiskCheck))
+		os.Exit(2)
+	}
+
+	if _, err := layout.Parse(cfg.Layout); err != nil {
+		ui.DisplayError(err)
+		os.Exit(2)
+	}
+
+	if _, err := cloner.NewBackend(cfg); err != nil {
+		ui.DisplayError(err)
+		os.Exit(2)
+	}
 
 	client := github.NewClient(cfg)
 
-	username, err := ui.PromptUsername()
+	source, err := client.Source(opts.source)
 	if err != nil {
 		ui.DisplayError(err)
-		os.Exit(1)
+		os.Exit(2)
 	}
 
-	ui.DisplayInfo(fmt.Sprintf("Checking if user '%s' exists...", username))
+	if opts.previewLayout {
+		runPreviewLayout(cfg, client, source, opts)
+		return
+	}
 
-	exists, err := client.UserExists(username)
-	if err != nil {
-		ui.DisplayError(fmt.Errorf("failed to check user existence: %w", err))
-		os.Exit(1)
+	if opts.dryRun {
+		runDryRun(cfg, client, source, opts, mode)
+		return
 	}
 
-	if !exists {
-		ui.DisplayError(fmt.Errorf("user or organization '%s' not found", username))
-		os.Exit(1)
+	if mode == ui.OutputTUI {
+		runInteractive(cfg, client, source, strings.Join(opts.owners, ", "))
+		return
 	}
 
-	ui.DisplayInfo("Fetching repositories...")
-	repos, err := client.GetRepositories(username)
-	if err != nil {
-		ui.DisplayError(fmt.Errorf("failed to fetch repositories: %w", err))
+	runBatch(cfg, client, source, opts, mode)
+}
+
+func runInteractive(cfg *config.Config, client *github.Client, source clone.Source, owner string) {
+	deps := ui.AppDeps{
+		Client: client,
+		Source: source,
+		LocalInfo: func(owner string) func(*models.Repository) *models.LocalRepoInfo {
+			return cloner.LocalInfoFunc(cfg, owner)
+		},
+		PlanPaths: func(owner string, repos []*models.Repository) (*layout.Plan, error) {
+			return cloner.NewManager(cfg).PlanPaths(repos, owner)
+		},
+		CheckDiskSpace: func(owner string, repos []*models.Repository) (*models.DiskSpaceReport, error) {
+			manager := cloner.NewManager(cfg)
+			actions, err := manager.Plan(repos, owner)
+			if err != nil {
+				return nil, err
+			}
+			return manager.CheckDiskSpace(actions)
+		},
+		Clone: func(ctx context.Context, repos []*models.Repository, owner string, events clone.Subscriber) error {
+			manager := cloner.NewManager(cfg, cloner.WithSubscriber(events))
+			return manager.CloneRepositoriesContext(ctx, repos, owner)
+		},
+	}
+
+	if err := ui.RunApp(deps, owner); err != nil {
+		ui.DisplayError(err)
 		os.Exit(1)
 	}
+}
 
-	if len(repos) == 0 {
-		ui.DisplayInfo("No repositories found for this user.")
+// runBatch clones every repository matching the filter without any prompts.
+func runBatch(cfg *config.Config, client *github.Client, source clone.Source, opts *options, mode ui.OutputMode) {
+	if len(opts.owners) == 0 {
+		ui.DisplayError(fmt.Errorf("an owner argument is required with --output %s", mode))
+		os.Exit(2)
+	}
+
+	var reporter clone.Subscriber = ui.NewPlainReporter(os.Stdout)
+	if mode == ui.OutputJSON {
+		reporter = ui.NewJSONReporter(os.Stdout)
+	}
+
+	filteredRepos, stats := fetchFiltered(client, source, opts)
+	for _, line := range ui.OwnerStatsLines(stats) {
+		reporter.Handle(clone.Message{Text: line})
+	}
+	if len(filteredRepos) == 0 {
+		reporter.Handle(clone.Message{Text: "No repositories match the selected filter."})
 		return
 	}
 
-	stats := github.CalculateStats(repos)
+	// Metrics decides the exit code so CI notices failed repositories.
+	metrics := &clone.Metrics{}
+	manager := cloner.NewManager(cfg, cloner.WithSubscriber(reporter), cloner.WithSubscriber(metrics))
+
+	if err := manager.CloneRepositories(filteredRepos, opts.owners[0]); err != nil {
+		ui.DisplayError(fmt.Errorf("cloning failed: %w", err))
+		os.Exit(1)
+	}
+
+	if len(opts.owners) > 1 {
+		for _, line := range ui.OwnerResultLines(opts.owners, manager.Results()) {
+			reporter.Handle(clone.Message{Text: line})
+		}
+	}
 
-	if err := ui.ShowRepositoryStats(stats, username); err != nil {
-		ui.DisplayError(fmt.Errorf("failed to display statistics: %w", err))
+	if metrics.Snapshot().Failed > 0 {
 		os.Exit(1)
 	}
+}
+
+// runPreviewLayout prints where each repository would be cloned without
+// touching the disk, flagging collisions.
+func runPreviewLayout(cfg *config.Config, client *github.Client, source clone.Source, opts *options) {
+	if len(opts.owners) == 0 {
+		ui.DisplayError(fmt.Errorf("an owner argument is required with --preview-layout"))
+		os.Exit(2)
+	}
+
+	repos, stats := fetchFiltered(client, source, opts)
+	warnFailedOwners(stats)
 
-	filterType, err := ui.ShowFilterSelector(stats)
+	plan, err := cloner.NewManager(cfg).PlanPaths(repos, opts.owners[0])
 	if err != nil {
-		ui.DisplayError(fmt.Errorf("filter selection failed: %w", err))
+		ui.DisplayError(err)
 		os.Exit(1)
 	}
 
-	filteredRepos := github.FilterRepositories(repos, filterType)
+	paths := make([]string, 0, len(plan.Paths))
+	for _, path := range plan.Paths {
+		paths = append(paths, path)
+	}
+	fmt.Print(layout.Tree(cfg.BaseDir, paths))
 
-	if len(filteredRepos) == 0 {
-		ui.DisplayInfo("No repositories match the selected filter.")
-		return
+	if err := plan.CollisionError(); err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
 	}
+}
+
+// runDryRun prints the plan for every repository matching the filter and
+// exits without touching the disk. Like a real run, it fails when space is
+// short under --disk-check abort.
+func runDryRun(cfg *config.Config, client *github.Client, source clone.Source, opts *options, mode ui.OutputMode) {
+	if len(opts.owners) == 0 {
+		ui.DisplayError(fmt.Errorf("an owner argument is required with --dry-run"))
+		os.Exit(2)
+	}
+
+	repos, stats := fetchFiltered(client, source, opts)
+	warnFailedOwners(stats)
+
+	manager := cloner.NewManager(cfg)
 
-	selectedRepos, err := ui.ShowRepositorySelector(filteredRepos, filterType)
+	actions, err := manager.Plan(repos, opts.owners[0])
 	if err != nil {
-		ui.DisplayError(fmt.Errorf("repository selection failed: %w", err))
+		ui.DisplayError(err)
 		os.Exit(1)
 	}
 
-	if len(selectedRepos) == 0 {
-		ui.DisplayInfo("No repositories selected for cloning.")
-		return
+	var report *models.DiskSpaceReport
+	if cfg.DiskCheck != "off" {
+		if report, err = manager.CheckDiskSpace(actions); err != nil {
+			ui.DisplayError(fmt.Errorf("skipping disk space check: %w", err))
+		}
 	}
 
-	ui.DisplaySuccess(fmt.Sprintf("Selected %d repositories for cloning", len(selectedRepos)))
+	if mode == ui.OutputJSON {
+		if err := ui.PrintPlanJSON(os.Stdout, actions, report); err != nil {
+			ui.DisplayError(err)
+			os.Exit(1)
+		}
+	} else {
+		ui.PrintPlan(os.Stdout, actions)
+		if report != nil {
+			fmt.Printf("Disk space: %s\n", report)
+		}
+	}
 
-	manager := cloner.NewManager(cfg)
+	if report != nil && !report.Sufficient() {
+		ui.DisplayError(fmt.Errorf("not enough disk space in %s", cfg.BaseDir))
+		if cfg.DiskCheck == "abort" {
+			os.Exit(1)
+		}
+	}
+}
 
-	ui.DisplayInfo("Starting repository cloning...")
-	if err := manager.CloneRepositories(selectedRepos, username); err != nil {
-		ui.DisplayError(fmt.Errorf("cloning failed: %w", err))
+// fetchFiltered lists every owner's repositories from source concurrently
+// and applies --filter. Owners that couldn't be listed are reported in the
+// stats' PerOwner; it exits only when none could. Each repository remembers
+// the owner it was listed for so it is cloned into that owner's directory.
+func fetchFiltered(client *github.Client, source clone.Source, opts *options) ([]*models.Repository, *models.RepositoryStats) {
+	filterType, err := models.ParseFilterType(opts.filter)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(2)
+	}
+
+	listings := clone.ListOwners(context.Background(), client.CheckedSource(source), opts.owners)
+	repos, err := clone.Merge(listings)
+	if err != nil && clone.AllFailed(listings) {
+		ui.DisplayError(err)
 		os.Exit(1)
 	}
 
-	ui.DisplaySuccess("All operations completed!")
+	return github.FilterRepositories(repos, filterType), github.CalculateListingStats(listings)
+}
+
+// warnFailedOwners reports owners that couldn't be listed on stderr, for
+// commands whose stdout is reserved for their output.
+func warnFailedOwners(stats *models.RepositoryStats) {
+	for _, owner := range stats.PerOwner {
+		if owner.Err != nil {
+			ui.DisplayError(fmt.Errorf("%s: %w", owner.Owner, owner.Err))
+		}
+	}
 }
 
 func init() {
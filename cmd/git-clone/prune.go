@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/internal/ui"
+	"github.com/chetanr25/mass-git-cloner/internal/workspace"
+)
+
+// runPrune implements `gclone prune owner`.
+func runPrune(args []string) {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	dir := fs.String("dir", ".", "base directory the owner was cloned into")
+	layoutSpec := fs.String("layout", layout.Default, "directory layout used when cloning, for placing renamed repositories")
+	yes := fs.Bool("yes", false, "apply every rename and archive without asking")
+	dryRun := fs.Bool("dry-run", false, "print what would change and exit")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gclone prune [flags] owner\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ui.ConfigureColor()
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	owner := fs.Arg(0)
+
+	cfg := config.DefaultConfig()
+	cfg.BaseDir = *dir
+	cfg.Layout = *layoutSpec
+
+	if _, err := layout.Parse(cfg.Layout); err != nil {
+		ui.DisplayError(err)
+		os.Exit(2)
+	}
+
+	client := github.NewClient(cfg)
+	repos, err := client.GetRepositories(owner)
+	if err != nil {
+		ui.DisplayError(fmt.Errorf("failed to fetch repositories: %w", err))
+		os.Exit(1)
+	}
+
+	// Reconcile against a snapshot and ask every question before taking the
+	// manifest lock, so concurrent clones aren't held up by the prompts.
+	man, err := manifest.Load(cfg.BaseDir)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	actions, err := workspace.Reconcile(ctx, cfg, client, man, owner, repos)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	if len(actions) == 0 {
+		ui.DisplayInfo("Every working copy matches its upstream repository.")
+		return
+	}
+
+	var approved []*workspace.PruneAction
+	for _, action := range actions {
+		if *dryRun || action.Kind == workspace.PruneUnknown {
+			printPruneAction(action)
+			continue
+		}
+
+		if action.Kind != workspace.PruneAdopt && !*yes {
+			printPruneAction(action)
+			if !ui.PromptConfirmation(pruneQuestion(action)) {
+				continue
+			}
+		}
+		approved = append(approved, action)
+	}
+
+	if len(approved) == 0 {
+		return
+	}
+
+	failed := 0
+	err = manifest.Update(cfg.BaseDir, func(man *manifest.Manifest) error {
+		for _, action := range approved {
+			if err := workspace.ApplyPrune(ctx, cfg, man, action); err != nil {
+				ui.DisplayError(err)
+				failed++
+				continue
+			}
+
+			switch action.Kind {
+			case workspace.PruneRename:
+				ui.DisplaySuccess(fmt.Sprintf("Renamed %s -> %s", action.Path, action.NewPath))
+			case workspace.PruneArchive:
+				ui.DisplaySuccess(fmt.Sprintf("Archived %s -> %s", action.Path, action.NewPath))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func printPruneAction(action *workspace.PruneAction) {
+	target := ""
+	if action.NewPath != "" {
+		target = " -> " + action.NewPath
+	}
+	fmt.Printf("%-8s %s%s (%s)\n", action.Kind, action.Path, target, action.Reason)
+}
+
+func pruneQuestion(action *workspace.PruneAction) string {
+	if action.Kind == workspace.PruneRename {
+		return fmt.Sprintf("Rename %s and update its remote?", action.Path)
+	}
+	return fmt.Sprintf("Move %s to the archive?", action.Path)
+}
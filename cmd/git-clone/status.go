@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/ui"
+	"github.com/chetanr25/mass-git-cloner/internal/workspace"
+)
+
+// runStatus implements `gclone status [dir]`.
+func runStatus(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	output := fs.String("output", string(ui.OutputAuto), "output mode: auto, tui, plain or json")
+	offline := fs.Bool("offline", false, "don't check whether each repository still exists on GitHub")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gclone status [flags] [dir]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ui.ConfigureColor()
+
+	mode, err := ui.ResolveOutputMode(*output)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(2)
+	}
+
+	root := "."
+	if fs.NArg() > 0 {
+		root = fs.Arg(0)
+	}
+
+	cfg := config.DefaultConfig()
+
+	var client *github.Client
+	if !*offline {
+		client = github.NewClient(cfg)
+	}
+
+	statuses, err := workspace.Status(context.Background(), cfg, client, root)
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+
+	switch mode {
+	case ui.OutputJSON:
+		err = ui.PrintStatusJSON(os.Stdout, statuses)
+	case ui.OutputTUI:
+		err = ui.ShowStatusTable(root, statuses)
+	default:
+		ui.PrintStatusTable(os.Stdout, root, statuses)
+	}
+
+	if err != nil {
+		ui.DisplayError(err)
+		os.Exit(1)
+	}
+}
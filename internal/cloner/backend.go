@@ -0,0 +1,20 @@
+package cloner
+
+import (
+	"fmt"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+)
+
+// NewBackend returns the executor selected by Config.Backend.
+func NewBackend(cfg *config.Config) (clone.Executor, error) {
+	switch cfg.Backend {
+	case "exec", "":
+		return &execBackend{config: cfg}, nil
+	case "go-git":
+		return &goGitBackend{config: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q (expected exec or go-git)", cfg.Backend)
+	}
+}
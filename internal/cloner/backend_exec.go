@@ -0,0 +1,115 @@
+package cloner
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// execBackend shells out to the git binary.
+type execBackend struct {
+	config *config.Config
+}
+
+func (b *execBackend) Check() error {
+	return CheckGitInstalled()
+}
+
+func (b *execBackend) Clone(ctx context.Context, repo *models.Repository, path string, onProgress func(models.TransferProgress)) error {
+	args := cloneArgs(b.config, repo, path)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = b.env()
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	lastMessage := watchProgress(stderr, onProgress)
+
+	if err := cmd.Wait(); err != nil {
+		if lastMessage != "" {
+			return fmt.Errorf("git clone failed: %s: %w", lastMessage, err)
+		}
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) Update(ctx context.Context, path string) error {
+	args := updateArgs(path)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = b.env()
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git pull failed: %w", err)
+	}
+
+	return nil
+}
+
+func (b *execBackend) Head(ctx context.Context, path string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "HEAD")
+	cmd.Env = b.env()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (b *execBackend) Remote(ctx context.Context, path string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "remote", "get-url", "origin")
+	cmd.Env = b.env()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git remote get-url failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// env passes the token to git through GIT_CONFIG_* rather than the command
+// line, so it never shows up in process listings or dry-run plans.
+func (b *execBackend) env() []string {
+	// Fail instead of waiting on a credentials prompt nobody will answer.
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if b.config.Token == "" {
+		return env
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + b.config.Token))
+	return append(env,
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.https://github.com/.extraheader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+	)
+}
+
+// cloneArgs and updateArgs are shared with dry-run plans so the command shown
+// is exactly the one that runs.
+func cloneArgs(cfg *config.Config, repo *models.Repository, repoPath string) []string {
+	args := []string{"git", "clone", "--progress"}
+	if cfg.CloneDepth > 0 {
+		args = append(args, "--depth", strconv.Itoa(cfg.CloneDepth))
+	}
+	if cfg.CloneBranch != "" {
+		args = append(args, "--branch", cfg.CloneBranch)
+	}
+	return append(args, repo.CloneURL, repoPath)
+}
+
+func updateArgs(repoPath string) []string {
+	return []string{"git", "-C", repoPath, "pull", "--ff-only"}
+}
@@ -0,0 +1,92 @@
+package cloner_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/cloner"
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func TestExecCloneReportsProgressAndGitErrors(t *testing.T) {
+	remotes := t.TempDir()
+	url := githubtest.BareRepo(t, remotes, "alpha")
+
+	cfg := config.DefaultConfig()
+	cfg.Backend = "exec"
+	cfg.Token = ""
+	target := t.TempDir()
+	gc := cloner.NewGitCloner(cfg)
+
+	var phases []models.ClonePhase
+	repo := &models.Repository{Name: "alpha", FullName: "octocat/alpha", CloneURL: url}
+	err := gc.CloneRepository(context.Background(), repo, filepath.Join(target, "alpha"), func(p models.TransferProgress) {
+		phases = append(phases, p.Phase)
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository: %v", err)
+	}
+	if len(phases) == 0 {
+		t.Error("no progress was reported from git's stderr")
+	}
+
+	missing := &models.Repository{Name: "missing", FullName: "octocat/missing", CloneURL: "file://" + filepath.ToSlash(filepath.Join(remotes, "missing.git"))}
+	path := filepath.Join(target, "missing")
+	err = gc.CloneRepository(context.Background(), missing, path, nil)
+	if err == nil {
+		t.Fatal("cloning a missing repository succeeded")
+	}
+	// The error carries git's own explanation, not just the exit status.
+	if !strings.Contains(err.Error(), "fatal:") {
+		t.Errorf("error = %v, want git's fatal message", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("failed clone left %s behind", path)
+	}
+}
+
+func TestExecCloneTimeoutKillsGit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of git")
+	}
+
+	// A git that answers --version but hangs on clone, like one stuck on a
+	// stalled connection.
+	bin := t.TempDir()
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep is not installed")
+	}
+	script := "#!/bin/sh\nif [ \"$1\" = --version ]; then echo 'git version 2.0.0'; exit 0; fi\nexec " + sleep + " 30\n"
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	cfg := config.DefaultConfig()
+	cfg.Backend = "exec"
+	cfg.CloneTimeout = 200 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "alpha")
+	repo := &models.Repository{Name: "alpha", FullName: "octocat/alpha", CloneURL: "https://github.com/octocat/alpha.git"}
+
+	start := time.Now()
+	err = cloner.NewGitCloner(cfg).CloneRepository(context.Background(), repo, path, nil)
+	if err == nil {
+		t.Fatal("clone succeeded although git never finished")
+	}
+	if elapsed := time.Since(start); elapsed < cfg.CloneTimeout || elapsed > 5*time.Second {
+		t.Errorf("clone returned after %s, want git killed at the %s timeout", elapsed, cfg.CloneTimeout)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("timed out clone left %s behind", path)
+	}
+}
@@ -0,0 +1,150 @@
+package cloner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// goGitBackend clones with go-git, so no git binary is needed.
+type goGitBackend struct {
+	config *config.Config
+}
+
+func (b *goGitBackend) Check() error {
+	return nil
+}
+
+func (b *goGitBackend) Clone(ctx context.Context, repo *models.Repository, path string, onProgress func(models.TransferProgress)) error {
+	opts := &git.CloneOptions{
+		URL:   repo.CloneURL,
+		Auth:  b.auth(repo.CloneURL),
+		Depth: b.config.CloneDepth,
+	}
+	if b.config.CloneBranch != "" {
+		opts.ReferenceName = plumbing.NewBranchReferenceName(b.config.CloneBranch)
+	}
+	// Like git, a shallow clone only fetches the branch being checked out.
+	opts.SingleBranch = b.config.CloneDepth > 0
+
+	pr, pw := io.Pipe()
+	opts.Progress = pw
+	done := make(chan struct{})
+	go func() {
+		watchProgress(pr, onProgress)
+		close(done)
+	}()
+
+	_, err := git.PlainCloneContext(ctx, path, false, opts)
+	pw.Close()
+	<-done
+
+	if err != nil {
+		return fmt.Errorf("go-git clone failed: %w", err)
+	}
+
+	if onProgress != nil {
+		onProgress(models.TransferProgress{Phase: models.PhaseDone, Percent: 100})
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) Update(ctx context.Context, path string) error {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("go-git pull failed: %w", err)
+	}
+
+	url := ""
+	if remote, err := repo.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+		url = remote.Config().URLs[0]
+	}
+
+	// go-git only fast-forwards, matching `git pull --ff-only`.
+	err = worktree.PullContext(ctx, &git.PullOptions{
+		RemoteName: "origin",
+		Auth:       b.auth(url),
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("go-git pull failed: %w", err)
+	}
+
+	return nil
+}
+
+func (b *goGitBackend) Head(ctx context.Context, path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("go-git failed to resolve HEAD: %w", err)
+	}
+
+	return head.Hash().String(), nil
+}
+
+func (b *goGitBackend) Remote(ctx context.Context, path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", fmt.Errorf("go-git open failed: %w", err)
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("go-git failed to read origin: %w", err)
+	}
+	if len(remote.Config().URLs) == 0 {
+		return "", fmt.Errorf("origin has no URL")
+	}
+
+	return remote.Config().URLs[0], nil
+}
+
+// cloneDescription and updateDescription say what Clone and Update will do,
+// for dry-run plans. They follow the options Clone and Update pass to go-git.
+func cloneDescription(cfg *config.Config, repo *models.Repository, repoPath string) string {
+	var options []string
+	if cfg.CloneDepth > 0 {
+		options = append(options, fmt.Sprintf("depth %d", cfg.CloneDepth))
+	}
+	if cfg.CloneBranch != "" {
+		options = append(options, "branch "+cfg.CloneBranch)
+	}
+
+	description := fmt.Sprintf("go-git clone %s into %s", repo.CloneURL, repoPath)
+	if len(options) > 0 {
+		description += " (" + strings.Join(options, ", ") + ")"
+	}
+	return description
+}
+
+func updateDescription(repoPath string) string {
+	return fmt.Sprintf("go-git fast-forward pull of origin in %s", repoPath)
+}
+
+// auth sends the token only to github.com over HTTPS, like the exec
+// backend's extraheader.
+func (b *goGitBackend) auth(url string) transport.AuthMethod {
+	if b.config.Token == "" || !strings.HasPrefix(url, "https://github.com/") {
+		return nil
+	}
+	return &http.BasicAuth{Username: "x-access-token", Password: b.config.Token}
+}
@@ -0,0 +1,30 @@
+package cloner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// existingGistPath finds an earlier clone of gist next to path. Gist
+// directories are named <id>-<description>, so editing the description
+// would otherwise clone the gist a second time under its new name.
+func existingGistPath(path string, gist *models.Gist) string {
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return path
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && (name == gist.ID || strings.HasPrefix(name, gist.ID+"-")) {
+			return filepath.Join(filepath.Dir(path), name)
+		}
+	}
+	return path
+}
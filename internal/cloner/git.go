@@ -6,44 +6,67 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"time"
 
 	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
 	"github.com/chetanr25/mass-git-cloner/pkg/models"
 )
 
 type GitCloner struct {
-	config *config.Config
+	config     *config.Config
+	backend    clone.Executor
+	backendErr error
 }
 
 func NewGitCloner(cfg *config.Config) *GitCloner {
+	backend, err := NewBackend(cfg)
 	return &GitCloner{
-		config: cfg,
+		config:     cfg,
+		backend:    backend,
+		backendErr: err,
 	}
 }
 
-func (g *GitCloner) CloneRepository(ctx context.Context, repo *models.Repository, targetDir string) error {
+// Check reports whether the configured backend can run, e.g. that the git
+// binary is installed for the exec backend.
+func (g *GitCloner) Check() error {
+	if g.backendErr != nil {
+		return g.backendErr
+	}
+	return g.backend.Check()
+}
 
-	repoPath := filepath.Join(targetDir, repo.Name)
+// CloneRepository clones repo into repoPath, passing transfer progress to
+// onProgress as it is reported.
+func (g *GitCloner) CloneRepository(ctx context.Context, repo *models.Repository, repoPath string, onProgress func(models.TransferProgress)) error {
+	if err := g.Check(); err != nil {
+		return err
+	}
 
 	if _, err := os.Stat(repoPath); err == nil {
 		return fmt.Errorf("directory already exists: %s", repoPath)
 	}
 
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+
 	cloneCtx, cancel := context.WithTimeout(ctx, g.config.CloneTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(cloneCtx, "git", "clone", repo.CloneURL, repoPath)
-
-	if err := cmd.Run(); err != nil {
+	if err := g.backend.Clone(cloneCtx, repo, repoPath, onProgress); err != nil {
 		os.RemoveAll(repoPath)
-		return fmt.Errorf("git clone failed: %w", err)
+		return err
 	}
 
 	return nil
 }
 
 func (g *GitCloner) UpdateRepository(ctx context.Context, repoPath string) error {
+	if err := g.Check(); err != nil {
+		return err
+	}
 
 	gitDir := filepath.Join(repoPath, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
@@ -53,14 +76,23 @@ func (g *GitCloner) UpdateRepository(ctx context.Context, repoPath string) error
 	updateCtx, cancel := context.WithTimeout(ctx, g.config.CloneTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(updateCtx, "git", "-C", repoPath, "pull", "--ff-only")
-	cmd.Env = os.Environ()
+	return g.backend.Update(updateCtx, repoPath)
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git pull failed: %w", err)
+// HeadCommit returns the SHA checked out in repoPath.
+func (g *GitCloner) HeadCommit(ctx context.Context, repoPath string) (string, error) {
+	if err := g.Check(); err != nil {
+		return "", err
 	}
+	return g.backend.Head(ctx, repoPath)
+}
 
-	return nil
+// OriginURL returns the URL of repoPath's origin remote.
+func (g *GitCloner) OriginURL(ctx context.Context, repoPath string) (string, error) {
+	if err := g.Check(); err != nil {
+		return "", err
+	}
+	return g.backend.Remote(ctx, repoPath)
 }
 
 func CheckGitInstalled() error {
@@ -71,18 +103,8 @@ func CheckGitInstalled() error {
 	return nil
 }
 
-func PrepareTargetDirectory(username, baseDir string) (string, error) {
-	targetDir := filepath.Join(baseDir, username)
-
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create target directory: %w", err)
-	}
-
-	return targetDir, nil
-}
-
-func GetRepositoryInfo(repoPath string) (*LocalRepoInfo, error) {
-	info := &LocalRepoInfo{
+func GetRepositoryInfo(repoPath string) (*models.LocalRepoInfo, error) {
+	info := &models.LocalRepoInfo{
 		Path:   repoPath,
 		Exists: false,
 	}
@@ -107,9 +129,22 @@ func GetRepositoryInfo(repoPath string) (*LocalRepoInfo, error) {
 	return info, nil
 }
 
-type LocalRepoInfo struct {
-	Path         string
-	Exists       bool
-	IsGitRepo    bool
-	LastModified time.Time
+// LocalInfoFunc returns a lookup reporting what already exists on disk at
+// each repository's layout path.
+func LocalInfoFunc(cfg *config.Config, username string) func(*models.Repository) *models.LocalRepoInfo {
+	l, err := layout.Parse(cfg.Layout)
+	return func(repo *models.Repository) *models.LocalRepoInfo {
+		if err != nil {
+			return nil
+		}
+		path, err := l.Path(cfg.BaseDir, username, repo)
+		if err != nil {
+			return nil
+		}
+		if repo.Gist != nil {
+			path = existingGistPath(path, repo.Gist)
+		}
+		info, _ := GetRepositoryInfo(path)
+		return info
+	}
 }
@@ -5,100 +5,347 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/chetanr25/mass-git-cloner/internal/config"
-	"github.com/chetanr25/mass-git-cloner/internal/ui"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
 	"github.com/chetanr25/mass-git-cloner/pkg/models"
 )
 
 type Manager struct {
-	config   *config.Config
-	cloner   *GitCloner
-	progress *ui.ProgressTracker
+	config      *config.Config
+	cloner      *GitCloner
+	wikiCloner  *GitCloner
+	source      clone.Source
+	subscribers []clone.Subscriber
+	publishMu   sync.Mutex
+	results     []*models.CloneResult
 }
 
-func NewManager(cfg *config.Config) *Manager {
-	return &Manager{
-		config: cfg,
-		cloner: NewGitCloner(cfg),
+// Option customises a Manager.
+type Option func(*Manager)
+
+// WithSubscriber adds a subscriber to the events of every run, e.g. the
+// interactive app's dashboard. Without subscribers events are discarded.
+func WithSubscriber(subscriber clone.Subscriber) Option {
+	return func(m *Manager) {
+		m.subscribers = append(m.subscribers, subscriber)
 	}
 }
 
-func (m *Manager) CloneRepositories(repos []*models.Repository, username string) error {
-	if len(repos) == 0 {
-		return fmt.Errorf("no repositories to clone")
+// WithExecutor replaces the git backend selected by Config.Backend.
+func WithExecutor(executor clone.Executor) Option {
+	return func(m *Manager) {
+		for _, c := range []*GitCloner{m.cloner, m.wikiCloner} {
+			c.backend = executor
+			c.backendErr = nil
+		}
 	}
+}
 
-	if err := CheckGitInstalled(); err != nil {
-		return err
+// WithSource replaces the GitHub API as the source of repositories.
+func WithSource(source clone.Source) Option {
+	return func(m *Manager) {
+		m.source = source
 	}
+}
 
-	targetDir, err := PrepareTargetDirectory(username, m.config.BaseDir)
-	if err != nil {
-		return fmt.Errorf("failed to prepare target directory: %w", err)
+func NewManager(cfg *config.Config, opts ...Option) *Manager {
+	// Wikis only have their default branch, so Config.CloneBranch would
+	// make every wiki clone fail.
+	wikiConfig := *cfg
+	wikiConfig.CloneBranch = ""
+
+	m := &Manager{
+		config:     cfg,
+		cloner:     NewGitCloner(cfg),
+		wikiCloner: NewGitCloner(&wikiConfig),
+		source:     github.NewClient(cfg),
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
 
-	ui.DisplayInfo(fmt.Sprintf("Cloning %d repositories to: %s", len(repos), targetDir))
+// publish delivers event to every subscriber, one event at a time.
+func (m *Manager) publish(event clone.Event) {
+	m.publishMu.Lock()
+	defer m.publishMu.Unlock()
 
+	for _, subscriber := range m.subscribers {
+		subscriber.Handle(event)
+	}
+}
+
+func (m *Manager) info(text string) {
+	m.publish(clone.Message{Text: text})
+}
+
+// Repositories lists owner's repositories from the configured source.
+func (m *Manager) Repositories(ctx context.Context, owner string) ([]*models.Repository, error) {
+	return m.source.Repositories(ctx, owner)
+}
+
+// CloneRepositories clones repos and stops early on SIGINT/SIGTERM.
+func (m *Manager) CloneRepositories(repos []*models.Repository, username string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	go func() {
-		<-sigChan
-		ui.DisplayInfo("\nReceived interrupt signal. Stopping...")
-		cancel()
-	}()
-
-	m.progress = ui.InitProgressTracker(len(repos))
-
-	for i, repo := range repos {
 		select {
+		case <-sigChan:
+			m.info("Received interrupt signal. Stopping...")
+			cancel()
 		case <-ctx.Done():
-			ui.DisplayInfo("Cloning stopped by user")
-			return nil
-		default:
 		}
+	}()
 
-		m.progress.Update(fmt.Sprintf("Cloning %s (%d/%d)...", repo.Name, i+1, len(repos)))
+	return m.CloneRepositoriesContext(ctx, repos, username)
+}
 
-		err := m.cloner.CloneRepository(ctx, repo, targetDir)
+// CloneRepositoriesContext clones repos until ctx is cancelled.
+func (m *Manager) CloneRepositoriesContext(ctx context.Context, repos []*models.Repository, username string) error {
+	m.results = nil
 
-		if err != nil {
-			m.progress.Failure(repo.Name, err)
-		} else {
-			m.progress.Success(repo.Name)
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories to clone")
+	}
+
+	if err := m.cloner.Check(); err != nil {
+		return err
+	}
+
+	paths, err := m.PlanPaths(repos, username)
+	if err != nil {
+		return err
+	}
+	if err := paths.CollisionError(); err != nil {
+		return err
+	}
+
+	actions, err := m.Plan(repos, username)
+	if err != nil {
+		return err
+	}
+
+	planned := make(map[int64]*models.PlannedAction, len(actions))
+	var work []*models.Repository
+	for _, action := range actions {
+		switch action.Action {
+		case models.ActionClone, models.ActionUpdate:
+			planned[action.Repository.ID] = action
+			work = append(work, action.Repository)
+		default:
+			m.info(fmt.Sprintf("Skipping %s: %s", action.Name, action.Reason))
 		}
 	}
 
-	m.progress.Finish()
+	if len(work) == 0 {
+		m.info("Nothing to clone; every repository is already present.")
+		return nil
+	}
+
+	if err := m.preflightDiskSpace(actions); err != nil {
+		return err
+	}
+
+	m.info(fmt.Sprintf("Cloning %d repositories to: %s", len(work), m.config.BaseDir))
+
+	m.run(ctx, work, func(ctx context.Context, repo *models.Repository) error {
+		action := planned[repo.ID]
+		if action.Action == models.ActionUpdate {
+			return m.cloner.UpdateRepository(ctx, action.Path)
+		}
+		return m.cloner.CloneRepository(ctx, repo, action.Path, m.transferEvents(repo))
+	}, func(ctx context.Context, repo *models.Repository) {
+		m.afterSync(ctx, repo, planned[repo.ID].Path)
+	})
 
 	return nil
 }
 
+// PlanPaths resolves each repository's target path with the configured
+// layout, keeping gists in the directories they were first cloned into.
+// Callers should check the plan's collisions before cloning.
+func (m *Manager) PlanPaths(repos []*models.Repository, username string) (*layout.Plan, error) {
+	l, err := layout.Parse(m.config.Layout)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := l.Plan(m.config.BaseDir, username, repos)
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range repos {
+		if repo.Gist != nil {
+			plan.Paths[repo.ID] = existingGistPath(plan.Paths[repo.ID], repo.Gist)
+		}
+	}
+	return plan, nil
+}
+
 func (m *Manager) UpdateRepositories(repos []*models.Repository, username string) error {
-	targetDir, err := PrepareTargetDirectory(username, m.config.BaseDir)
+	plan, err := m.PlanPaths(repos, username)
 	if err != nil {
 		return err
 	}
 
-	ctx := context.Background()
-	progress := ui.InitProgressTracker(len(repos))
+	m.run(context.Background(), repos, func(ctx context.Context, repo *models.Repository) error {
+		return m.cloner.UpdateRepository(ctx, plan.Paths[repo.ID])
+	}, func(ctx context.Context, repo *models.Repository) {
+		m.afterSync(ctx, repo, plan.Paths[repo.ID])
+	})
 
-	for i, repo := range repos {
-		repoPath := fmt.Sprintf("%s/%s", targetDir, repo.Name)
-		progress.Update(fmt.Sprintf("Updating %s (%d/%d)...", repo.Name, i+1, len(repos)))
+	return nil
+}
 
-		if err := m.cloner.UpdateRepository(ctx, repoPath); err != nil {
-			progress.Failure(repo.Name, err)
+// afterSync records a working copy that was just cloned or updated and
+// syncs its wiki. Neither is retried with the clone or fails the repository,
+// since the working copy is already in place.
+func (m *Manager) afterSync(ctx context.Context, repo *models.Repository, path string) {
+	if err := m.recordSynced(ctx, repo, path); err != nil {
+		m.info(fmt.Sprintf("Warning: %s: %v", repo.DisplayName(), err))
+	}
+	m.syncWiki(ctx, repo, path)
+}
+
+// recordSynced stores repo and its checked out commit in the manifest so
+// later runs can match the directory to its upstream.
+func (m *Manager) recordSynced(ctx context.Context, repo *models.Repository, path string) error {
+	sha, _ := m.cloner.HeadCommit(ctx, path)
+
+	err := manifest.Update(m.config.BaseDir, func(man *manifest.Manifest) error {
+		man.Record(path, repo, sha)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cloned but failed to update manifest: %w", err)
+	}
+	return nil
+}
+
+// Results returns the outcome of each repository processed by the last run,
+// in the order they finished.
+func (m *Manager) Results() []*models.CloneResult {
+	return m.results
+}
+
+// run publishes the events of a run around forEachRepository.
+func (m *Manager) run(ctx context.Context, repos []*models.Repository, work func(context.Context, *models.Repository) error, done func(context.Context, *models.Repository)) {
+	start := time.Now()
+
+	m.publish(clone.RunStarted{Total: len(repos)})
+	for _, repo := range repos {
+		m.publish(clone.RepoQueued{Repository: repo})
+	}
+
+	m.forEachRepository(ctx, repos, work, done)
+
+	finished := clone.RunFinished{
+		Duration:  time.Since(start),
+		Cancelled: ctx.Err() != nil,
+	}
+	for _, result := range m.results {
+		if result.Success {
+			finished.Succeeded++
 		} else {
-			progress.Success(repo.Name)
+			finished.Failed++
 		}
 	}
 
-	progress.Finish()
-	return nil
+	if finished.Cancelled {
+		m.info("Stopped by user")
+	}
+	m.publish(finished)
+}
+
+// transferEvents turns a backend's progress callbacks for repo into
+// PhaseChanged and BytesReceived events.
+func (m *Manager) transferEvents(repo *models.Repository) func(models.TransferProgress) {
+	var last models.TransferProgress
+	return func(p models.TransferProgress) {
+		switch {
+		case p.Phase == models.PhaseReceiving && p.BytesReceived != last.BytesReceived:
+			if p.Phase != last.Phase {
+				m.publish(clone.PhaseChanged{Repository: repo, Phase: p.Phase, Percent: p.Percent})
+			}
+			m.publish(clone.BytesReceived{Repository: repo, Bytes: p.BytesReceived, BytesPerSec: p.BytesPerSec, Percent: p.Percent})
+		case p.Phase != last.Phase || p.Percent != last.Percent:
+			m.publish(clone.PhaseChanged{Repository: repo, Phase: p.Phase, Percent: p.Percent})
+		}
+		last = p
+	}
+}
+
+// forEachRepository runs work for every repo on up to Config.Concurrency
+// workers, retrying transient failures, then done once work succeeds. Only
+// work is retried. Once ctx is cancelled no further repositories are
+// started.
+func (m *Manager) forEachRepository(ctx context.Context, repos []*models.Repository, work func(context.Context, *models.Repository) error, done func(context.Context, *models.Repository)) {
+	workers := m.config.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan *models.Repository)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	m.results = nil
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				m.publish(clone.RepoStarted{Repository: repo})
+
+				start := time.Now()
+				attempts, err := m.withRetry(ctx, repo, func() error {
+					return work(ctx, repo)
+				})
+				if err == nil {
+					done(ctx, repo)
+				}
+				duration := time.Since(start)
+
+				mu.Lock()
+				m.results = append(m.results, &models.CloneResult{
+					Repository: repo,
+					Success:    err == nil,
+					Error:      err,
+					Duration:   duration,
+					Attempts:   attempts,
+				})
+				mu.Unlock()
+
+				if err != nil {
+					m.publish(clone.RepoFailed{Repository: repo, Err: err, Duration: duration, Attempts: attempts})
+				} else {
+					m.publish(clone.RepoSucceeded{Repository: repo, Duration: duration, Attempts: attempts})
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, repo := range repos {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- repo:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
 }
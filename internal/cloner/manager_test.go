@@ -0,0 +1,375 @@
+package cloner_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/internal/cloner"
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// setup serves octocat's repositories from the fake API, backed by local
+// bare repositories, and returns a config cloning into a temp directory.
+func setup(t *testing.T, names ...string) (*config.Config, *github.Client) {
+	t.Helper()
+
+	remotes := t.TempDir()
+	server := githubtest.NewServer(t)
+	for _, name := range names {
+		server.AddRepos("octocat", &models.Repository{
+			Name:     name,
+			CloneURL: githubtest.BareRepo(t, remotes, name),
+		})
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.BaseDir = t.TempDir()
+	cfg.Token = ""
+
+	return cfg, github.NewClient(cfg)
+}
+
+func TestCloneRepositoriesEndToEnd(t *testing.T) {
+	for _, backend := range []string{"exec", "go-git"} {
+		t.Run(backend, func(t *testing.T) {
+			testCloneEndToEnd(t, backend)
+		})
+	}
+}
+
+func testCloneEndToEnd(t *testing.T, backend string) {
+	cfg, client := setup(t, "alpha", "beta", "gamma")
+	cfg.Backend = backend
+
+	repos, err := client.GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+
+	manager := cloner.NewManager(cfg)
+	if err := manager.CloneRepositoriesContext(context.Background(), repos, "octocat"); err != nil {
+		t.Fatalf("CloneRepositoriesContext: %v", err)
+	}
+
+	if n := len(manager.Results()); n != 3 {
+		t.Fatalf("got %d results, want 3", n)
+	}
+	for _, result := range manager.Results() {
+		if !result.Success || result.Attempts != 1 {
+			t.Errorf("%s: success=%v attempts=%d err=%v", result.Repository.Name, result.Success, result.Attempts, result.Error)
+		}
+	}
+
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		if _, err := os.Stat(filepath.Join(cfg.BaseDir, "octocat", name, "README.md")); err != nil {
+			t.Errorf("%s was not checked out: %v", name, err)
+		}
+	}
+
+	man, err := manifest.Load(cfg.BaseDir)
+	if err != nil {
+		t.Fatalf("manifest.Load: %v", err)
+	}
+	entry := man.Get(filepath.Join(cfg.BaseDir, "octocat", "alpha"))
+	if entry == nil || entry.FullName != "octocat/alpha" || len(entry.LastSyncedSHA) != 40 {
+		t.Errorf("manifest entry for alpha = %+v", entry)
+	}
+
+	// A second run finds everything already cloned.
+	if err := manager.CloneRepositoriesContext(context.Background(), repos, "octocat"); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if n := len(manager.Results()); n != 0 {
+		t.Errorf("second run processed %d repositories, want 0", n)
+	}
+}
+
+func TestCloneRepositoriesReportsFailures(t *testing.T) {
+	cfg, client := setup(t, "alpha")
+	cfg.RetryBackoff = 0
+
+	repos, err := client.GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+	repos = append(repos, &models.Repository{
+		ID:       1,
+		Name:     "missing",
+		FullName: "octocat/missing",
+		CloneURL: "file://" + filepath.ToSlash(filepath.Join(t.TempDir(), "missing.git")),
+	})
+
+	manager := cloner.NewManager(cfg)
+	if err := manager.CloneRepositoriesContext(context.Background(), repos, "octocat"); err != nil {
+		t.Fatalf("CloneRepositoriesContext: %v", err)
+	}
+
+	if n := len(manager.Results()); n != 2 {
+		t.Fatalf("got %d results, want 2", n)
+	}
+	for _, result := range manager.Results() {
+		switch result.Repository.Name {
+		case "alpha":
+			if !result.Success {
+				t.Errorf("alpha failed: %v", result.Error)
+			}
+		case "missing":
+			if result.Success || result.Attempts != 1 {
+				t.Errorf("missing: success=%v attempts=%d; want a single failed attempt", result.Success, result.Attempts)
+			}
+			if _, err := os.Stat(filepath.Join(cfg.BaseDir, "octocat", "missing")); !os.IsNotExist(err) {
+				t.Errorf("partial clone of missing was left behind")
+			}
+		}
+	}
+}
+
+func TestCloneRepositoriesWithWikis(t *testing.T) {
+	for _, backend := range []string{"exec", "go-git"} {
+		t.Run(backend, func(t *testing.T) {
+			remotes := t.TempDir()
+			repos := []*models.Repository{
+				{ID: 1, Name: "alpha", FullName: "octocat/alpha", HasWiki: true, CloneURL: githubtest.BareRepo(t, remotes, "alpha")},
+				{ID: 2, Name: "beta", FullName: "octocat/beta", HasWiki: true, CloneURL: githubtest.BareRepo(t, remotes, "beta")},
+			}
+			githubtest.BareRepo(t, remotes, "alpha.wiki")
+
+			cfg := config.DefaultConfig()
+			cfg.BaseDir = t.TempDir()
+			cfg.Backend = backend
+			cfg.CloneWikis = true
+			cfg.RetryBackoff = 0
+
+			var messages []string
+			manager := cloner.NewManager(cfg, cloner.WithSubscriber(clone.SubscriberFunc(func(event clone.Event) {
+				if msg, ok := event.(clone.Message); ok {
+					messages = append(messages, msg.Text)
+				}
+			})))
+			if err := manager.CloneRepositoriesContext(context.Background(), repos, "octocat"); err != nil {
+				t.Fatalf("CloneRepositoriesContext: %v", err)
+			}
+
+			for _, result := range manager.Results() {
+				if !result.Success {
+					t.Errorf("%s failed: %v", result.Repository.Name, result.Error)
+				}
+			}
+
+			if _, err := os.Stat(filepath.Join(cfg.BaseDir, "octocat", "alpha.wiki", "README.md")); err != nil {
+				t.Errorf("alpha's wiki was not cloned: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(cfg.BaseDir, "octocat", "beta.wiki")); !os.IsNotExist(err) {
+				t.Errorf("beta.wiki exists although beta has no wiki pages")
+			}
+			if !slices.Contains(messages, "No wiki pages for octocat/beta") {
+				t.Errorf("messages = %q, want a note that beta has no wiki pages", messages)
+			}
+		})
+	}
+}
+
+func TestWikisIgnoreCloneBranch(t *testing.T) {
+	for _, backend := range []string{"exec", "go-git"} {
+		t.Run(backend, func(t *testing.T) {
+			remotes := t.TempDir()
+			repo := &models.Repository{ID: 1, Name: "alpha", FullName: "octocat/alpha", HasWiki: true, CloneURL: githubtest.BareRepo(t, remotes, "alpha")}
+			githubtest.BareRepo(t, remotes, "alpha.wiki")
+			// Only the repository has the requested branch, as on GitHub.
+			if out, err := exec.Command("git", "-C", filepath.Join(remotes, "alpha.git"), "branch", "dev", "main").CombinedOutput(); err != nil {
+				t.Fatalf("git branch: %v\n%s", err, out)
+			}
+
+			cfg := config.DefaultConfig()
+			cfg.BaseDir = t.TempDir()
+			cfg.Backend = backend
+			cfg.CloneBranch = "dev"
+			cfg.CloneWikis = true
+			cfg.RetryBackoff = 0
+
+			var messages []string
+			manager := cloner.NewManager(cfg, cloner.WithSubscriber(clone.SubscriberFunc(func(event clone.Event) {
+				if msg, ok := event.(clone.Message); ok {
+					messages = append(messages, msg.Text)
+				}
+			})))
+			if err := manager.CloneRepositoriesContext(context.Background(), []*models.Repository{repo}, "octocat"); err != nil {
+				t.Fatalf("CloneRepositoriesContext: %v", err)
+			}
+
+			if result := manager.Results()[0]; !result.Success {
+				t.Fatalf("alpha failed: %v", result.Error)
+			}
+			if _, err := os.Stat(filepath.Join(cfg.BaseDir, "octocat", "alpha.wiki", "README.md")); err != nil {
+				t.Errorf("wiki was not cloned with --branch set: %v (messages %q)", err, messages)
+			}
+		})
+	}
+}
+
+func TestCheckDiskSpaceCountsOnlyNewClones(t *testing.T) {
+	cfg, client := setup(t, "alpha", "beta")
+	cfg.DiskSpaceMargin = 0.5
+
+	repos, err := client.GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+
+	manager := cloner.NewManager(cfg)
+	if err := manager.CloneRepositories(repos[:1], "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+
+	repos[0].Size, repos[1].Size = 4096, 2048
+	actions, err := manager.Plan(repos, "octocat")
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	report, err := manager.CheckDiskSpace(actions)
+	if err != nil {
+		t.Fatalf("CheckDiskSpace: %v", err)
+	}
+
+	if want := uint64(2048 * 1024); report.Projected != want || report.Required != want*3/2 {
+		t.Errorf("projected %d, required %d; want %d without the clone of %s and %d with the margin",
+			report.Projected, report.Required, want, repos[0].Name, want*3/2)
+	}
+}
+
+func TestPlanReadsOriginWithoutGitBinary(t *testing.T) {
+	cfg, client := setup(t, "alpha")
+	cfg.Backend = "go-git"
+
+	repos, err := client.GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+
+	manager := cloner.NewManager(cfg)
+	if err := manager.CloneRepositories(repos, "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	moved := *repos[0]
+	moved.CloneURL = "https://github.com/someone-else/alpha.git"
+
+	actions, err := manager.Plan([]*models.Repository{&moved}, "octocat")
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if actions[0].Action != models.ActionConflict {
+		t.Errorf("action = %s (%s), want a conflict for the other origin", actions[0].Action, actions[0].Reason)
+	}
+
+	actions, err = manager.Plan(repos, "octocat")
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if actions[0].Action != models.ActionSkipExists {
+		t.Errorf("action = %s (%s), want skip-exists for the same origin", actions[0].Action, actions[0].Reason)
+	}
+}
+
+func TestPlanDescribesBackend(t *testing.T) {
+	repo := &models.Repository{ID: 1, Name: "alpha", FullName: "octocat/alpha", CloneURL: "https://github.com/octocat/alpha.git"}
+
+	tests := []struct {
+		backend     string
+		command     string
+		description string
+	}{
+		{"exec", "git clone --progress --depth 1 --branch dev https://github.com/octocat/alpha.git ", ""},
+		{"go-git", "", "go-git clone https://github.com/octocat/alpha.git into "},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.BaseDir = t.TempDir()
+		cfg.Backend = tt.backend
+		cfg.CloneDepth = 1
+		cfg.CloneBranch = "dev"
+
+		actions, err := cloner.NewManager(cfg).Plan([]*models.Repository{repo}, "octocat")
+		if err != nil {
+			t.Fatalf("%s: Plan: %v", tt.backend, err)
+		}
+		action := actions[0]
+		command := strings.Join(action.Command, " ")
+		if (tt.command == "") != (command == "") || !strings.HasPrefix(command, tt.command) {
+			t.Errorf("%s: command = %q, want %q…", tt.backend, command, tt.command)
+		}
+		if !strings.HasPrefix(action.Description, tt.description) ||
+			(tt.description != "" && !strings.HasSuffix(action.Description, "(depth 1, branch dev)")) {
+			t.Errorf("%s: description = %q", tt.backend, action.Description)
+		}
+	}
+}
+
+func TestGistKeepsItsDirectoryWhenRenamed(t *testing.T) {
+	gist := &models.Gist{ID: "aa5a315d61ae9438b18d", Description: "Hello world", Owner: models.Owner{Login: "octocat"}}
+	gist.GitPullURL = githubtest.BareRepo(t, t.TempDir(), gist.ID)
+
+	cfg := config.DefaultConfig()
+	cfg.BaseDir = t.TempDir()
+	cfg.Layout = "owner"
+	if err := cloner.NewManager(cfg).CloneRepositories([]*models.Repository{gist.Repository()}, "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+	cloned := filepath.Join(cfg.BaseDir, "octocat", "gists", "aa5a315d61ae9438b18d-hello-world")
+	if _, err := os.Stat(cloned); err != nil {
+		t.Fatalf("gist was not cloned as <id>-<slug>: %v", err)
+	}
+
+	gist.Description = "Hello again"
+	actions, err := cloner.NewManager(cfg).Plan([]*models.Repository{gist.Repository()}, "octocat")
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if actions[0].Path != cloned || actions[0].Action != models.ActionSkipExists {
+		t.Errorf("renamed gist = %s %s, want skip-exists in %s", actions[0].Action, actions[0].Path, cloned)
+	}
+}
+
+func TestManifestFailureDoesNotFailClone(t *testing.T) {
+	cfg, client := setup(t, "alpha")
+
+	repos, err := client.GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+
+	// A file where the manifest directory belongs makes every write fail.
+	if err := os.WriteFile(filepath.Join(cfg.BaseDir, manifest.Dir), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	manager := cloner.NewManager(cfg, cloner.WithSubscriber(clone.SubscriberFunc(func(event clone.Event) {
+		if msg, ok := event.(clone.Message); ok {
+			messages = append(messages, msg.Text)
+		}
+	})))
+	if err := manager.CloneRepositories(repos, "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+
+	result := manager.Results()[0]
+	if !result.Success || result.Attempts != 1 {
+		t.Errorf("success=%v attempts=%d err=%v, want one successful attempt", result.Success, result.Attempts, result.Error)
+	}
+	if !slices.ContainsFunc(messages, func(text string) bool { return strings.HasPrefix(text, "Warning:") }) {
+		t.Errorf("messages = %q, want a warning about the manifest", messages)
+	}
+}
@@ -0,0 +1,113 @@
+package cloner
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Plan works out what a run would do with each repository without touching
+// the disk: clone new ones, update or skip existing working copies, and flag
+// conflicts. CloneRepositoriesContext executes the same plan.
+func (m *Manager) Plan(repos []*models.Repository, username string) ([]*models.PlannedAction, error) {
+	paths, err := m.PlanPaths(repos, username)
+	if err != nil {
+		return nil, err
+	}
+
+	collided := make(map[int64]bool)
+	for _, c := range paths.Collisions {
+		for _, repo := range c.Repositories {
+			collided[repo.ID] = true
+		}
+	}
+
+	ctx := context.Background()
+	actions := make([]*models.PlannedAction, 0, len(repos))
+	for _, repo := range repos {
+		path := paths.Paths[repo.ID]
+		action := &models.PlannedAction{
+			Repository: repo,
+			Name:       repo.DisplayName(),
+			Path:       path,
+		}
+
+		info, _ := GetRepositoryInfo(path)
+
+		matches := true
+		if info.IsGitRepo && !collided[repo.ID] {
+			if matches, err = m.originMatches(ctx, path, repo); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case collided[repo.ID]:
+			action.Action = models.ActionConflict
+			action.Reason = "another repository maps to the same path"
+		case !info.Exists:
+			action.Action = models.ActionClone
+			action.EstimatedBytes = int64(repo.Size) * 1024
+			if m.config.Backend == "go-git" {
+				action.Description = cloneDescription(m.config, repo, path)
+			} else {
+				action.Command = cloneArgs(m.config, repo, path)
+			}
+		case !info.IsGitRepo:
+			action.Action = models.ActionSkipNotGit
+			action.Reason = "path exists but is not a git repository"
+		case !matches:
+			action.Action = models.ActionConflict
+			action.Reason = "existing working copy has a different origin remote"
+		case m.config.UpdateExisting:
+			action.Action = models.ActionUpdate
+			if m.config.Backend == "go-git" {
+				action.Description = updateDescription(path)
+			} else {
+				action.Command = updateArgs(path)
+			}
+		default:
+			action.Action = models.ActionSkipExists
+			action.Reason = "already cloned"
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// originMatches reports whether the working copy at path points at repo,
+// reading the remote through the backend so go-git needs no git binary. If
+// the working copy has no readable origin we assume it matches rather than
+// invent a conflict; a backend that can't run is an error.
+func (m *Manager) originMatches(ctx context.Context, path string, repo *models.Repository) (bool, error) {
+	if err := m.cloner.Check(); err != nil {
+		return false, err
+	}
+
+	remote, err := m.cloner.OriginURL(ctx, path)
+	if err != nil {
+		return true, nil
+	}
+
+	origin := normalizeRemote(remote)
+	return origin == normalizeRemote(repo.CloneURL) || origin == normalizeRemote(repo.SSHURL), nil
+}
+
+// normalizeRemote reduces https and scp-style ssh URLs to host/owner/name so
+// equivalent remotes compare equal.
+func normalizeRemote(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname() + u.Path)
+	}
+
+	if at := strings.Index(remote, "@"); at >= 0 {
+		remote = remote[at+1:]
+	}
+	return strings.ToLower(strings.Replace(remote, ":", "/", 1))
+}
@@ -0,0 +1,55 @@
+package cloner
+
+import (
+	"fmt"
+
+	"github.com/chetanr25/mass-git-cloner/internal/diskspace"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// CheckDiskSpace sums the estimated size of every planned clone. Repository
+// sizes come from the API and are only approximate, hence the margin.
+func (m *Manager) CheckDiskSpace(actions []*models.PlannedAction) (*models.DiskSpaceReport, error) {
+	var projected uint64
+	for _, action := range actions {
+		if action.Action == models.ActionClone {
+			projected += uint64(action.EstimatedBytes)
+		}
+	}
+
+	available, err := diskspace.Free(m.config.BaseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read free disk space: %w", err)
+	}
+
+	return &models.DiskSpaceReport{
+		Projected: projected,
+		Required:  uint64(float64(projected) * (1 + m.config.DiskSpaceMargin)),
+		Available: available,
+	}, nil
+}
+
+// preflightDiskSpace applies Config.DiskCheck: abort returns an error when
+// space is short, warn only reports it.
+func (m *Manager) preflightDiskSpace(actions []*models.PlannedAction) error {
+	if m.config.DiskCheck == "off" {
+		return nil
+	}
+
+	report, err := m.CheckDiskSpace(actions)
+	if err != nil {
+		m.info(fmt.Sprintf("Warning: skipping disk space check: %v", err))
+		return nil
+	}
+
+	if report.Sufficient() {
+		return nil
+	}
+
+	if m.config.DiskCheck == "warn" {
+		m.info(fmt.Sprintf("Warning: not enough disk space: %s", report))
+		return nil
+	}
+
+	return fmt.Errorf("not enough disk space in %s: %s (use --disk-check warn to continue anyway)", m.config.BaseDir, report)
+}
@@ -0,0 +1,141 @@
+package cloner
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+var (
+	percentPattern    = regexp.MustCompile(`(\d+)%`)
+	transferPattern   = regexp.MustCompile(`([\d.]+) (GiB|MiB|KiB|bytes)\b`)
+	throughputPattern = regexp.MustCompile(`([\d.]+) (GiB|MiB|KiB|bytes)/s`)
+)
+
+var phasePrefixes = []struct {
+	prefix string
+	phase  models.ClonePhase
+}{
+	{"Enumerating objects", models.PhaseCounting},
+	{"Counting objects", models.PhaseCounting},
+	{"Compressing objects", models.PhaseCompressing},
+	{"Receiving objects", models.PhaseReceiving},
+	{"Resolving deltas", models.PhaseResolving},
+	{"Updating files", models.PhaseCheckout},
+}
+
+// parseProgressLine turns one line of `git clone --progress` output into a
+// TransferProgress. Lines that don't describe a known phase are ignored.
+// The "remote: " prefix git adds to server messages is optional, since
+// go-git passes the server's sideband output through as-is.
+func parseProgressLine(line string) (models.TransferProgress, bool) {
+	line = strings.TrimPrefix(strings.TrimSpace(line), "remote: ")
+
+	var progress models.TransferProgress
+	found := false
+	for _, p := range phasePrefixes {
+		if strings.HasPrefix(line, p.prefix) {
+			progress.Phase = p.phase
+			found = true
+			break
+		}
+	}
+	if !found {
+		return progress, false
+	}
+
+	if m := percentPattern.FindStringSubmatch(line); m != nil {
+		progress.Percent, _ = strconv.Atoi(m[1])
+	}
+
+	if progress.Phase == models.PhaseReceiving {
+		if m := transferPattern.FindStringSubmatch(line); m != nil {
+			progress.BytesReceived = int64(parseSize(m[1], m[2]))
+		}
+		if m := throughputPattern.FindStringSubmatch(line); m != nil {
+			progress.BytesPerSec = parseSize(m[1], m[2])
+		}
+	}
+
+	return progress, true
+}
+
+func parseSize(value, unit string) float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+
+	switch unit {
+	case "KiB":
+		return n * 1024
+	case "MiB":
+		return n * 1024 * 1024
+	case "GiB":
+		return n * 1024 * 1024 * 1024
+	default:
+		return n
+	}
+}
+
+// scanProgressLines splits on both \r and \n since git redraws progress in
+// place with carriage returns.
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// watchProgress reads git's stderr, reporting phase changes to onProgress and
+// returning the first fatal/error line (or else the last non-progress line)
+// so failures can say why git gave up.
+func watchProgress(r io.Reader, onProgress func(models.TransferProgress)) string {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanProgressLines)
+
+	var last models.TransferProgress
+	lastMessage := ""
+	fatalMessage := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		progress, ok := parseProgressLine(line)
+		if !ok {
+			lastMessage = strings.TrimSpace(line)
+			if fatalMessage == "" && (strings.HasPrefix(lastMessage, "fatal:") || strings.HasPrefix(lastMessage, "error:")) {
+				fatalMessage = lastMessage
+			}
+			continue
+		}
+
+		if progress.Phase == last.Phase && progress.Percent == last.Percent && progress.BytesReceived == last.BytesReceived {
+			continue
+		}
+		last = progress
+
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+
+	if fatalMessage != "" {
+		return fatalMessage
+	}
+	return lastMessage
+}
@@ -0,0 +1,74 @@
+package cloner
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want models.TransferProgress
+		ok   bool
+	}{
+		{"remote: Enumerating objects: 1234, done.", models.TransferProgress{Phase: models.PhaseCounting}, true},
+		{"remote: Counting objects:  45% (555/1234)", models.TransferProgress{Phase: models.PhaseCounting, Percent: 45}, true},
+		{"Compressing objects: 100% (80/80), done.", models.TransferProgress{Phase: models.PhaseCompressing, Percent: 100}, true},
+		{
+			"Receiving objects:  37% (457/1234), 1.50 MiB | 512.00 KiB/s",
+			models.TransferProgress{Phase: models.PhaseReceiving, Percent: 37, BytesReceived: 1572864, BytesPerSec: 524288},
+			true,
+		},
+		{
+			"Receiving objects: 100% (1234/1234), 812 bytes | 812.00 bytes/s, done.",
+			models.TransferProgress{Phase: models.PhaseReceiving, Percent: 100, BytesReceived: 812, BytesPerSec: 812},
+			true,
+		},
+		{"Resolving deltas:  12% (3/25)", models.TransferProgress{Phase: models.PhaseResolving, Percent: 12}, true},
+		{"Updating files:  60% (6/10)", models.TransferProgress{Phase: models.PhaseCheckout, Percent: 60}, true},
+		{"Cloning into 'hello'...", models.TransferProgress{}, false},
+		{"fatal: repository 'x' not found", models.TransferProgress{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseProgressLine(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseProgressLine(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWatchProgress(t *testing.T) {
+	stderr := "Cloning into 'hello'...\n" +
+		"Receiving objects:  10% (1/10)\rReceiving objects:  10% (1/10)\rReceiving objects:  50% (5/10), 1.00 KiB | 1.00 KiB/s\r" +
+		"Receiving objects: 100% (10/10), 2.00 KiB | 1.00 KiB/s, done.\n" +
+		"error: RPC failed; curl 56 GnuTLS recv error\n" +
+		"fatal: early EOF\n"
+
+	var phases []models.TransferProgress
+	message := watchProgress(strings.NewReader(stderr), func(p models.TransferProgress) {
+		phases = append(phases, p)
+	})
+
+	percents := make([]int, len(phases))
+	for i, p := range phases {
+		percents[i] = p.Percent
+	}
+	// The repeated 10% line is reported once.
+	if !slices.Equal(percents, []int{10, 50, 100}) {
+		t.Errorf("reported percents = %v, want [10 50 100]", percents)
+	}
+	if phases[2].BytesReceived != 2048 {
+		t.Errorf("BytesReceived = %d, want 2048", phases[2].BytesReceived)
+	}
+	if message != "error: RPC failed; curl 56 GnuTLS recv error" {
+		t.Errorf("message = %q, want the first error line", message)
+	}
+
+	if message := watchProgress(strings.NewReader("Cloning into 'hello'...\nwarning: You appear to have cloned an empty repository.\n"), nil); message != "warning: You appear to have cloned an empty repository." {
+		t.Errorf("message without errors = %q, want the last line", message)
+	}
+}
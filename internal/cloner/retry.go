@@ -0,0 +1,87 @@
+package cloner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// transientMessages are fragments of git and network errors that usually go
+// away on a second try.
+var transientMessages = []string{
+	"connection reset",
+	"connection refused",
+	"connection timed out",
+	"operation timed out",
+	"early eof",
+	"unexpected disconnect",
+	"the remote end hung up unexpectedly",
+	"rpc failed",
+	"temporary failure in name resolution",
+	"could not resolve host",
+	"tls handshake timeout",
+	"unexpected eof",
+}
+
+// retryStatusPattern matches HTTP 5xx and 429 (too many requests) statuses
+// as git and go-git report them.
+var retryStatusPattern = regexp.MustCompile(`(?i)(http|error:?|status(?: code)?:?) (5\d\d|429)\b|returned error: (5\d\d|429)`)
+
+// IsTransient reports whether err looks like a network hiccup rather than a
+// problem retrying can't fix, such as a missing repository or bad
+// credentials.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ETIMEDOUT) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, fragment := range transientMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+
+	return retryStatusPattern.MatchString(message)
+}
+
+// withRetry runs fn until it succeeds, fails with a permanent error or
+// Config.MaxAttempts is reached, doubling Config.RetryBackoff between
+// attempts. GitCloner removes partial clones on failure, so each attempt
+// starts from an empty directory. It returns the number of attempts made.
+func (m *Manager) withRetry(ctx context.Context, repo *models.Repository, fn func() error) (int, error) {
+	maxAttempts := m.config.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	backoff := m.config.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxAttempts || !IsTransient(err) {
+			return attempt, err
+		}
+
+		m.info(fmt.Sprintf("Retrying %s in %s (attempt %d/%d): %v",
+			repo.DisplayName(), backoff, attempt+1, maxAttempts, err))
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
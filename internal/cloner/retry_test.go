@@ -0,0 +1,47 @@
+package cloner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/internal/cloner"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"could not resolve host", errors.New("git clone failed: fatal: unable to access 'https://github.com/octocat/hello.git/': Could not resolve host: github.com: exit status 128"), true},
+		{"name resolution", errors.New("dial tcp: lookup github.com: Temporary failure in name resolution"), true},
+		{"early EOF", errors.New("git clone failed: fatal: early EOF: exit status 128"), true},
+		{"unexpected disconnect", errors.New("fetch-pack: unexpected disconnect while reading sideband packet"), true},
+		{"connection reset", fmt.Errorf("go-git clone failed: %w", syscall.ECONNRESET), true},
+		{"unexpected EOF", fmt.Errorf("go-git clone failed: %w", io.ErrUnexpectedEOF), true},
+		{"HTTP 502", errors.New("fatal: unable to access 'https://github.com/octocat/hello.git/': The requested URL returned error: 502"), true},
+		{"HTTP 503 from go-git", errors.New("go-git clone failed: unexpected client error: unexpected requesting \"https://github.com/octocat/hello.git/info/refs\" status code: 503"), true},
+		{"HTTP 429", errors.New("fatal: unable to access 'https://github.com/octocat/hello.git/': The requested URL returned error: 429"), true},
+		{"HTTP 401", errors.New("fatal: unable to access 'https://github.com/octocat/hello.git/': The requested URL returned error: 401"), false},
+		{"HTTP 403", errors.New("fatal: unable to access 'https://github.com/octocat/hello.git/': The requested URL returned error: 403"), false},
+		{"authentication failed", errors.New("git clone failed: fatal: Authentication failed for 'https://github.com/octocat/hello.git/': exit status 128"), false},
+		{"go-git authentication required", fmt.Errorf("go-git clone failed: %w", transport.ErrAuthenticationRequired), false},
+		{"repository not found", errors.New("git clone failed: remote: Repository not found. fatal: repository 'https://github.com/octocat/hello.git/' not found: exit status 128"), false},
+		{"go-git repository not found", fmt.Errorf("go-git clone failed: %w", transport.ErrRepositoryNotFound), false},
+		{"cancelled", fmt.Errorf("git clone failed: %w", context.Canceled), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cloner.IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package cloner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// WikiURL returns the clone URL of repo's wiki, which GitHub serves as a
+// separate <name>.wiki.git repository.
+func WikiURL(repo *models.Repository) string {
+	return strings.TrimSuffix(repo.CloneURL, ".git") + ".wiki.git"
+}
+
+// WikiPath is where the wiki of the repository at repoPath is cloned.
+func WikiPath(repoPath string) string {
+	return repoPath + ".wiki"
+}
+
+// wikiRepository describes repo's wiki as a repository of its own.
+func wikiRepository(repo *models.Repository) *models.Repository {
+	wiki := *repo
+	wiki.Name = repo.Name + ".wiki"
+	wiki.FullName = repo.FullName + ".wiki"
+	wiki.CloneURL = WikiURL(repo)
+	wiki.SSHURL = ""
+	wiki.HasWiki = false
+	return &wiki
+}
+
+// syncWiki clones or updates the wiki next to repoPath when wikis are
+// enabled. Problems are reported as messages rather than failing repo:
+// GitHub answers "not found" for a wiki that is enabled but has no pages.
+func (m *Manager) syncWiki(ctx context.Context, repo *models.Repository, repoPath string) {
+	if !m.config.CloneWikis || !repo.HasWiki || repo.Gist != nil {
+		return
+	}
+
+	wiki := wikiRepository(repo)
+	path := WikiPath(repoPath)
+
+	_, err := m.withRetry(ctx, wiki, func() error {
+		if _, err := os.Stat(path); err == nil {
+			return m.wikiCloner.UpdateRepository(ctx, path)
+		}
+		return m.wikiCloner.CloneRepository(ctx, wiki, path, nil)
+	})
+
+	switch {
+	case err == nil:
+	case isMissingRepository(err):
+		m.info(fmt.Sprintf("No wiki pages for %s", repo.DisplayName()))
+	default:
+		m.info(fmt.Sprintf("Failed to sync wiki for %s: %v", repo.DisplayName(), err))
+	}
+}
+
+// isMissingRepository reports whether err says the remote repository does
+// not exist.
+func isMissingRepository(err error) bool {
+	if errors.Is(err, transport.ErrRepositoryNotFound) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "repository") && strings.Contains(msg, "not found") ||
+		strings.Contains(msg, "does not appear to be a git repository")
+}
@@ -1,23 +1,83 @@
 package config
 
 import (
+	"os"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	CloneTimeout time.Duration
-	APITimeout   time.Duration
-	BaseDir      string
+	CloneTimeout   time.Duration
+	APITimeout     time.Duration
+	BaseDir        string
+	Layout         string
+	Concurrency    int
+	UpdateExisting bool
+	// DiskCheck is "abort", "warn" or "off"; DiskSpaceMargin is the extra
+	// fraction of free space required on top of the projected clone size.
+	DiskCheck       string
+	DiskSpaceMargin float64
+	// Backend is "exec" (the git binary) or "go-git" (pure Go, no git
+	// required). CloneDepth 0 clones full history; an empty CloneBranch
+	// checks out the default branch.
+	Backend     string
+	CloneDepth  int
+	CloneBranch string
+	// CloneWikis also clones the wiki of each repository that has one
+	// enabled into <repo>.wiki next to it.
+	CloneWikis bool
+	// MaxAttempts is how often a clone is tried when it fails with a
+	// transient network error; RetryBackoff is the first delay between
+	// attempts and doubles after each one.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// APIBaseURL is the GitHub REST endpoint; tests point it at a fake
+	// server.
+	APIBaseURL string
+	// Token authenticates API requests and HTTPS clones; it defaults to
+	// GITHUB_TOKEN.
+	Token string
+	// Owners are cloned when none are given on the command line; they
+	// default to the comma-separated GCLONE_OWNERS.
+	Owners []string
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		CloneTimeout: 10 * time.Minute,
-		APITimeout:   30 * time.Second,
-		BaseDir:      ".",
+		CloneTimeout:    10 * time.Minute,
+		APITimeout:      30 * time.Second,
+		BaseDir:         ".",
+		Layout:          "default",
+		Concurrency:     4,
+		DiskCheck:       "abort",
+		DiskSpaceMargin: 0.2,
+		Backend:         "exec",
+		MaxAttempts:     3,
+		RetryBackoff:    2 * time.Second,
+		APIBaseURL:      GitHubAPIBaseURL,
+		Token:           os.Getenv("GITHUB_TOKEN"),
+		Owners:          ParseOwners(os.Getenv("GCLONE_OWNERS")),
 	}
 }
 
+// ParseOwners splits comma-separated lists of owners, dropping blanks and
+// repeats while keeping the order they were given in.
+func ParseOwners(values ...string) []string {
+	var owners []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, owner := range strings.Split(value, ",") {
+			owner = strings.TrimSpace(owner)
+			if owner == "" || seen[strings.ToLower(owner)] {
+				continue
+			}
+			seen[strings.ToLower(owner)] = true
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
 const (
 	AppName          = "mass-git-cloner"
 	AppVersion       = "1.0.0"
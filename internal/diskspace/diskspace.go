@@ -0,0 +1,29 @@
+package diskspace
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Free returns the bytes available to the current user on the filesystem
+// holding path. path doesn't have to exist yet; the nearest existing parent
+// is measured instead.
+func Free(path string) (uint64, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return free(dir)
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || openbsd || dragonfly || windows)
+
+package diskspace
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func free(string) (uint64, error) {
+	return 0, fmt.Errorf("free disk space is not supported on %s", runtime.GOOS)
+}
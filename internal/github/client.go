@@ -1,12 +1,19 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
 	"github.com/chetanr25/mass-git-cloner/pkg/models"
 )
 
@@ -14,6 +21,11 @@ type Client struct {
 	httpClient *http.Client
 
 	baseURL string
+	token   string
+
+	// searchResumeAt is when the search rate limit resets after running out.
+	searchMu       sync.Mutex
+	searchResumeAt time.Time
 }
 
 func NewClient(cfg *config.Config) *Client {
@@ -22,7 +34,8 @@ func NewClient(cfg *config.Config) *Client {
 			Timeout: cfg.APITimeout,
 		},
 
-		baseURL: config.GitHubAPIBaseURL,
+		baseURL: strings.TrimSuffix(cfg.APIBaseURL, "/"),
+		token:   cfg.Token,
 	}
 }
 
@@ -42,67 +55,370 @@ func (c *Client) UserExists(username string) (bool, error) {
 	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK, nil
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, apiError(resp)
+	}
+}
+
+// RepositoryExists checks whether owner/name is still reachable on GitHub.
+func (c *Client) RepositoryExists(fullName string) (bool, error) {
+	url := fmt.Sprintf("%s/repos/%s", c.baseURL, fullName)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, apiError(resp)
+	}
+}
+
+// ErrNotFound is returned when GitHub answers 404. For a private
+// repository that the token can't see, GitHub also answers 404.
+var ErrNotFound = errors.New("not found")
+
+// Authenticated reports whether requests carry a token, without which
+// private repositories are neither listed nor found.
+func (c *Client) Authenticated() bool {
+	return c.token != ""
+}
+
+// Repository fetches owner/name on its own. Unlike listings, this includes
+// the parent of a fork. GitHub redirects renamed and transferred
+// repositories, so the result may have a different full name.
+func (c *Client) Repository(ctx context.Context, fullName string) (*models.Repository, error) {
+	return c.getRepository(ctx, "/repos/"+fullName, fullName)
+}
+
+// RepositoryByID fetches a repository by its ID, which survives renames
+// and transfers.
+func (c *Client) RepositoryByID(ctx context.Context, id int64) (*models.Repository, error) {
+	return c.getRepository(ctx, fmt.Sprintf("/repositories/%d", id), fmt.Sprintf("id %d", id))
+}
+
+func (c *Client) getRepository(ctx context.Context, path, name string) (*models.Repository, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("repository %s %w", name, ErrNotFound)
+	default:
+		return nil, apiError(resp)
+	}
+
+	var repo models.Repository
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
 }
 
 func (c *Client) GetRepositories(username string) ([]*models.Repository, error) {
-	var allRepos []*models.Repository
-	page := 1
+	return c.Repositories(context.Background(), username)
+}
 
-	for {
-		repos, hasMore, err := c.getRepositoriesPage(username, page)
+// Repositories fetches every page of username's repositories until ctx is
+// done. It makes Client a clone.Source. With a token the GraphQL API is
+// used, which also returns languages, the latest release and the default
+// branch's commit. Without one, or when GraphQL fails for any reason, such
+// as a server without it, an outage or a secondary rate limit, REST is
+// used. Both list the same repositories: with a token, the private ones it
+// can see, i.e. username's own when the token is theirs and an
+// organization's when it belongs to a member.
+func (c *Client) Repositories(ctx context.Context, username string) ([]*models.Repository, error) {
+	if c.token != "" {
+		repos, err := c.graphQLRepositories(ctx, username)
+		if err == nil || ctx.Err() != nil {
+			return repos, err
+		}
+	}
+
+	path := fmt.Sprintf("/users/%s/repos?sort=updated", username)
+	if c.token != "" {
+		login, err := c.authenticatedLogin(ctx)
 		if err != nil {
 			return nil, err
 		}
+		// /users/{username}/repos lists only public repositories, even
+		// for their owner or an organization's members.
+		if strings.EqualFold(login, username) {
+			path = "/user/repos?affiliation=owner&sort=updated"
+		} else if owner, err := c.user(ctx, "/users/"+username); err != nil {
+			return nil, err
+		} else if owner.Type == "Organization" {
+			path = fmt.Sprintf("/orgs/%s/repos?type=all&sort=updated", username)
+		}
+	}
+	return getAllPages[*models.Repository](ctx, c, path, "")
+}
+
+// starredMediaType makes /starred include when each star was given.
+const starredMediaType = "application/vnd.github.star+json"
 
-		allRepos = append(allRepos, repos...)
+// Starred fetches every repository username has starred, most recently
+// starred first, with StarredAt set.
+func (c *Client) Starred(ctx context.Context, username string) ([]*models.Repository, error) {
+	type star struct {
+		StarredAt time.Time          `json:"starred_at"`
+		Repo      *models.Repository `json:"repo"`
+	}
+
+	path := fmt.Sprintf("/users/%s/starred?sort=created", username)
+	stars, err := getAllPages[star](ctx, c, path, starredMediaType)
+	if err != nil {
+		return nil, err
+	}
 
-		if !hasMore {
-			break
+	repos := make([]*models.Repository, 0, len(stars))
+	for _, s := range stars {
+		s.Repo.StarredAt = s.StarredAt
+		repos = append(repos, s.Repo)
+	}
+	return repos, nil
+}
+
+// Gists fetches username's gists as repositories. When the token belongs to
+// username, secret gists are included too.
+func (c *Client) Gists(ctx context.Context, username string) ([]*models.Repository, error) {
+	path := fmt.Sprintf("/users/%s/gists", username)
+	if c.token != "" {
+		login, err := c.authenticatedLogin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(login, username) {
+			path = "/gists"
 		}
-		page++
 	}
 
-	return allRepos, nil
+	gists, err := getAllPages[*models.Gist](ctx, c, path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]*models.Repository, 0, len(gists))
+	for _, gist := range gists {
+		repos = append(repos, gist.Repository())
+	}
+	return repos, nil
 }
 
-func (c *Client) getRepositoriesPage(username string, page int) ([]*models.Repository, bool, error) {
-	url := fmt.Sprintf("%s/users/%s/repos?per_page=%d&page=%d&sort=updated",
-		c.baseURL, username, config.PerPage, page)
+// authenticatedLogin returns the login the token belongs to.
+func (c *Client) authenticatedLogin(ctx context.Context) (string, error) {
+	user, err := c.user(ctx, "/user")
+	if err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// user fetches the account at path, /user or /users/{username}.
+func (c *Client) user(ctx context.Context, path string) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	c.setHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, false, fmt.Errorf("GitHub API error: %d", resp.StatusCode)
+		return nil, apiError(resp)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var user User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Source names accepted by Client.Source. Team and topic sources take an
+// argument, as in "team:platform" or "topic:cli", and list repositories of
+// the organization given as the owner. A search source such as
+// "search:language:go pushed:>2024-01-01" is limited to the owner unless the
+// query names its own.
+const (
+	SourceRepos   = "repos"
+	SourceStarred = "starred"
+	SourceGists   = "gists"
+	SourceTeam    = "team"
+	SourceTopic   = "topic"
+	SourceSearch  = "search"
+)
+
+// Source returns the repository list named by a --source flag.
+func (c *Client) Source(name string) (clone.Source, error) {
+	kind, arg, hasArg := strings.Cut(name, ":")
+	if hasArg && arg == "" {
+		return nil, fmt.Errorf("source %q needs a name after the colon", name)
+	}
+
+	switch {
+	case !hasArg && (kind == SourceRepos || kind == ""):
+		return clone.SourceFunc(c.Repositories), nil
+	case !hasArg && kind == SourceStarred:
+		return clone.SourceFunc(c.Starred), nil
+	case !hasArg && kind == SourceGists:
+		return clone.SourceFunc(c.Gists), nil
+	case hasArg && kind == SourceTeam:
+		return clone.SourceFunc(func(ctx context.Context, org string) ([]*models.Repository, error) {
+			return c.TeamRepositories(ctx, org, arg)
+		}), nil
+	case hasArg && kind == SourceTopic:
+		return clone.SourceFunc(func(ctx context.Context, org string) ([]*models.Repository, error) {
+			return c.TopicRepositories(ctx, org, arg)
+		}), nil
+	case hasArg && kind == SourceSearch:
+		return clone.SourceFunc(func(ctx context.Context, owner string) ([]*models.Repository, error) {
+			return c.SearchRepositories(ctx, ScopeSearch(arg, owner))
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown source %q (expected repos, starred, gists, team:<slug>, topic:<name> or search:<query>)", name)
+	}
+}
+
+// CheckedSource makes source confirm that the owner exists before listing,
+// so a mistyped name reads as "not found" rather than an API error.
+func (c *Client) CheckedSource(source clone.Source) clone.Source {
+	return clone.SourceFunc(func(ctx context.Context, owner string) ([]*models.Repository, error) {
+		exists, err := c.UserExists(owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("user or organization '%s' not found", owner)
+		}
+
+		repos, err := source.Repositories(ctx, owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+		}
+		return repos, nil
+	})
+}
+
+// getAllPages follows page numbers until a short page, decoding each into
+// a slice of T. accept overrides the default media type when set.
+func getAllPages[T any](ctx context.Context, c *Client, path, accept string) ([]T, error) {
+	var all []T
+
+	for page := 1; ; page++ {
+		items, err := getPage[T](ctx, c, path, accept, page)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+
+		if len(items) < config.PerPage {
+			return all, nil
+		}
+	}
+}
+
+func getPage[T any](ctx context.Context, c *Client, path, accept string, page int) ([]T, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	url := fmt.Sprintf("%s%s%sper_page=%d&page=%d", c.baseURL, path, separator, config.PerPage, page)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, false, err
+		return nil, err
+	}
+
+	c.setHeaders(req)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
 	}
 
-	var repos []*models.Repository
-	if err := json.Unmarshal(body, &repos); err != nil {
-		return nil, false, err
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	hasMore := len(repos) == config.PerPage
+	var items []T
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, err
+	}
 
-	return repos, hasMore, nil
+	return items, nil
 }
 
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 	req.Header.Set("User-Agent", config.UserAgent)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+// apiError describes a failed response, calling out exhausted rate limits
+// since those are the most common reason for a 403.
+func apiError(resp *http.Response) error {
+	var body struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body)
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			return fmt.Errorf("GitHub API rate limit exceeded; resets at %s (set GITHUB_TOKEN for a higher limit)",
+				time.Unix(reset, 0).Format(time.Kitchen))
+		}
+	}
+
+	if body.Message != "" {
+		return fmt.Errorf("GitHub API error: %d: %s", resp.StatusCode, body.Message)
+	}
+	return fmt.Errorf("GitHub API error: %d", resp.StatusCode)
 }
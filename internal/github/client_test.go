@@ -0,0 +1,482 @@
+package github_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func newClient(server *githubtest.Server) *github.Client {
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = ""
+	return github.NewClient(cfg)
+}
+
+func TestGetRepositoriesPaginates(t *testing.T) {
+	server := githubtest.NewServer(t)
+	for i := 0; i < 250; i++ {
+		server.AddRepos("octocat", &models.Repository{Name: fmt.Sprintf("repo-%03d", i)})
+	}
+
+	repos, err := newClient(server).GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+
+	if len(repos) != 250 {
+		t.Fatalf("got %d repositories, want 250", len(repos))
+	}
+	if repos[249].FullName != "octocat/repo-249" {
+		t.Errorf("last repository = %q, want octocat/repo-249", repos[249].FullName)
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Errorf("made %d requests, want 3 pages", got)
+	}
+}
+
+func TestUserExists(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddUser("octocat")
+	server.AddOrg("github")
+	server.Forbid("/users/blocked")
+	client := newClient(server)
+
+	tests := []struct {
+		owner   string
+		want    bool
+		wantErr bool
+	}{
+		{"octocat", true, false},
+		{"github", true, false},
+		{"nobody", false, false},
+		{"blocked", false, true},
+	}
+
+	for _, tt := range tests {
+		got, err := client.UserExists(tt.owner)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UserExists(%q) error = %v, wantErr %v", tt.owner, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("UserExists(%q) = %v, want %v", tt.owner, got, tt.want)
+		}
+	}
+}
+
+func TestRepositoryExists(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{Name: "hello-world"})
+	client := newClient(server)
+
+	if ok, err := client.RepositoryExists("octocat/hello-world"); err != nil || !ok {
+		t.Errorf("RepositoryExists(hello-world) = %v, %v; want true", ok, err)
+	}
+	if ok, err := client.RepositoryExists("octocat/gone"); err != nil || ok {
+		t.Errorf("RepositoryExists(gone) = %v, %v; want false", ok, err)
+	}
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{Name: "hello-world"})
+	server.SetRateLimit(0)
+
+	_, err := newClient(server).GetRepositories("octocat")
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Fatalf("GetRepositories error = %v, want rate limit error", err)
+	}
+}
+
+func TestStarred(t *testing.T) {
+	server := githubtest.NewServer(t)
+	linux := &models.Repository{Name: "linux"}
+	server.AddRepos("torvalds", linux)
+	gopher := &models.Repository{Name: "go"}
+	server.AddRepos("golang", gopher)
+
+	starredAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	server.Star("octocat", starredAt, linux, gopher)
+
+	source, err := newClient(server).Source(github.SourceStarred)
+	if err != nil {
+		t.Fatalf("Source: %v", err)
+	}
+
+	repos, err := source.Repositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Repositories: %v", err)
+	}
+
+	if len(repos) != 2 {
+		t.Fatalf("got %d repositories, want 2", len(repos))
+	}
+	if repos[0].FullName != "torvalds/linux" || repos[1].FullName != "golang/go" {
+		t.Errorf("got %s and %s, want torvalds/linux and golang/go", repos[0].FullName, repos[1].FullName)
+	}
+	if !repos[0].StarredAt.Equal(starredAt) {
+		t.Errorf("StarredAt = %v, want %v", repos[0].StarredAt, starredAt)
+	}
+}
+
+func TestGists(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddGists("octocat",
+		&models.Gist{
+			ID:          "aa5a315d61ae9438b18d",
+			Description: "Hello, World! in Go",
+			Public:      true,
+			Files:       map[string]models.GistFile{"hello.go": {Filename: "hello.go", Language: "Go", Size: 2048}},
+		},
+		&models.Gist{ID: "bb6b426e72bf0549c29e", Public: false},
+	)
+	server.AddToken("secret-token", "octocat")
+
+	repos, err := newClient(server).Gists(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Gists: %v", err)
+	}
+	if len(repos) != 1 {
+		t.Fatalf("got %d gists without a token, want only the public one", len(repos))
+	}
+
+	gist := repos[0]
+	if gist.Name != "aa5a315d61ae9438b18d-hello-world-in-go" || gist.Language != "Go" || gist.Size != 2 {
+		t.Errorf("gist repository = name %q, language %q, size %d", gist.Name, gist.Language, gist.Size)
+	}
+	if gist.CloneURL != "https://gist.github.com/aa5a315d61ae9438b18d.git" {
+		t.Errorf("CloneURL = %q", gist.CloneURL)
+	}
+
+	l, _ := layout.Parse(layout.Default)
+	path, err := l.Path("src", "octocat", gist)
+	if err != nil {
+		t.Fatalf("Path: %v", err)
+	}
+	if want := filepath.Join("src", "octocat", "gists", gist.Name); path != want {
+		t.Errorf("Path = %q, want %q", path, want)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = "secret-token"
+	repos, err = github.NewClient(cfg).Gists(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Gists with token: %v", err)
+	}
+	if len(repos) != 2 || !repos[1].IsPrivate {
+		t.Errorf("got %d gists with the owner's token, want 2 including the secret one", len(repos))
+	}
+}
+
+func TestTeamAndTopicSources(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddOrg("acme")
+	api := &models.Repository{Name: "api", Topics: []string{"go", "backend"}}
+	web := &models.Repository{Name: "web", Topics: []string{"frontend"}}
+	worker := &models.Repository{Name: "worker", Topics: []string{"go"}}
+	server.AddRepos("acme", api, web, worker)
+	server.AddRepos("other", &models.Repository{Name: "tool", Topics: []string{"go"}})
+	server.AddTeam("acme", "platform", "Platform", api, worker)
+
+	client := newClient(server)
+
+	teams, err := client.Teams(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Teams: %v", err)
+	}
+	if len(teams) != 1 || teams[0].Slug != "platform" {
+		t.Fatalf("Teams = %+v, want the platform team", teams)
+	}
+
+	for _, tc := range []struct {
+		source string
+		want   []string
+	}{
+		{"team:platform", []string{"api", "worker"}},
+		{"topic:go", []string{"api", "worker"}},
+		{"topic:frontend", []string{"web"}},
+	} {
+		source, err := client.Source(tc.source)
+		if err != nil {
+			t.Fatalf("Source(%q): %v", tc.source, err)
+		}
+		repos, err := source.Repositories(context.Background(), "acme")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.source, err)
+		}
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s = %v, want %v", tc.source, names, tc.want)
+		}
+	}
+
+	if !slices.Contains(server.Requests(), "/search/repositories?q=org%3Aacme+topic%3Ago&per_page=100&page=1") {
+		t.Errorf("topic source did not use the search API: %v", server.Requests())
+	}
+
+	for _, name := range []string{"team:", "topic", "teams:platform"} {
+		if _, err := client.Source(name); err == nil {
+			t.Errorf("Source(%q) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestSearchSplitsPastResultCap(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddOrg("bigorg")
+	start := time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2500; i++ {
+		language := "Go"
+		if i%5 == 0 {
+			language = "Rust"
+		}
+		server.AddRepos("bigorg", &models.Repository{
+			Name:      fmt.Sprintf("repo-%04d", i),
+			Language:  language,
+			CreatedAt: start.Add(time.Duration(i) * 6 * time.Hour),
+		})
+	}
+
+	client := newClient(server)
+
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"language:go", 2000},
+		// Day 250 starts at repo 1000, of which 4 in 5 are Go.
+		{"language:go created:>=2015-09-08", 1200},
+	} {
+		source, err := client.Source("search:" + tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repos, err := source.Repositories(context.Background(), "bigorg")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.query, err)
+		}
+
+		seen := make(map[int64]bool)
+		for _, repo := range repos {
+			if seen[repo.ID] || repo.Language != "Go" {
+				t.Fatalf("%s: got %s (%s) twice or with the wrong language", tc.query, repo.Name, repo.Language)
+			}
+			seen[repo.ID] = true
+		}
+		if len(repos) != tc.want {
+			t.Errorf("%s: got %d repositories, want %d", tc.query, len(repos), tc.want)
+		}
+	}
+
+	split := false
+	for _, path := range server.Requests() {
+		split = split || strings.Contains(path, "created%3A2015-")
+	}
+	if !split {
+		t.Error("no request searched a creation date range")
+	}
+}
+
+func TestSearchFailsWhenOneSecondExceedsResultCap(t *testing.T) {
+	server := githubtest.NewServer(t)
+	created := time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 1001; i++ {
+		server.AddRepos("octocat", &models.Repository{Name: fmt.Sprintf("repo-%04d", i), CreatedAt: created})
+	}
+	server.AddRepos("octocat", &models.Repository{Name: "later", CreatedAt: created.AddDate(1, 0, 0)})
+
+	repos, err := newClient(server).SearchRepositories(context.Background(), "user:octocat")
+	if err == nil {
+		t.Fatalf("got %d repositories, want an error instead of a truncated result", len(repos))
+	}
+	if !strings.Contains(err.Error(), "2015-01-01T00:00:00Z") {
+		t.Errorf("error = %v, want it to name the range", err)
+	}
+}
+
+func TestSearchWaitsForRateLimit(t *testing.T) {
+	server := githubtest.NewServer(t)
+	for i := 0; i < 250; i++ {
+		server.AddRepos("octocat", &models.Repository{Name: fmt.Sprintf("repo-%d", i)})
+	}
+	server.SetSearchRateLimit(2, 100*time.Millisecond)
+
+	repos, err := newClient(server).SearchRepositories(context.Background(), "user:octocat")
+	if err != nil {
+		t.Fatalf("SearchRepositories: %v", err)
+	}
+	if len(repos) != 250 {
+		t.Errorf("got %d repositories, want 250", len(repos))
+	}
+}
+
+func TestRepositoriesUseGraphQLWithToken(t *testing.T) {
+	server := githubtest.NewServer(t)
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	server.AddRepos("octocat", &models.Repository{
+		Name:          "hello-world",
+		Language:      "Go",
+		DefaultBranch: "main",
+		HeadSHA:       "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Topics:        []string{"cli", "git"},
+		Languages:     []models.LanguageSize{{Name: "Go", Bytes: 9200}, {Name: "Shell", Bytes: 800}},
+		LatestRelease: &models.Release{TagName: "v1.2.0", Name: "Spring", PublishedAt: published},
+	})
+	var languages []models.LanguageSize
+	for i := range 12 {
+		languages = append(languages, models.LanguageSize{Name: fmt.Sprintf("lang-%02d", i), Bytes: 100})
+	}
+	server.AddRepos("octocat", &models.Repository{Name: "polyglot", Languages: languages})
+	for i := 2; i < 150; i++ {
+		server.AddRepos("octocat", &models.Repository{Name: fmt.Sprintf("repo-%03d", i)})
+	}
+	server.AddToken("secret-token", "octocat")
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = "secret-token"
+	repos, err := github.NewClient(cfg).Repositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Repositories: %v", err)
+	}
+
+	if len(repos) != 150 {
+		t.Fatalf("got %d repositories, want 150", len(repos))
+	}
+	if got := server.Requests(); !slices.Equal(got, []string{"/graphql", "/graphql"}) {
+		t.Errorf("requests = %v, want two GraphQL pages", got)
+	}
+
+	repo := repos[0]
+	if repo.FullName != "octocat/hello-world" || repo.CloneURL != "https://github.com/octocat/hello-world.git" {
+		t.Errorf("repository = %q cloned from %q", repo.FullName, repo.CloneURL)
+	}
+	if repo.HeadSHA != "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d" || repo.DefaultBranch != "main" {
+		t.Errorf("default branch = %q at %q", repo.DefaultBranch, repo.HeadSHA)
+	}
+	if len(repo.Languages) != 2 || repo.Languages[1] != (models.LanguageSize{Name: "Shell", Bytes: 800}) {
+		t.Errorf("Languages = %v", repo.Languages)
+	}
+	if repo.LanguageTotal != 10000 {
+		t.Errorf("LanguageTotal = %d, want 10000", repo.LanguageTotal)
+	}
+	if polyglot := repos[1]; len(polyglot.Languages) != 10 || polyglot.LanguageTotal != 1200 {
+		t.Errorf("polyglot has %d languages totalling %d, want the largest 10 of 1200 bytes", len(polyglot.Languages), polyglot.LanguageTotal)
+	}
+	if repo.LatestRelease == nil || repo.LatestRelease.TagName != "v1.2.0" || !repo.LatestRelease.PublishedAt.Equal(published) {
+		t.Errorf("LatestRelease = %+v", repo.LatestRelease)
+	}
+	if !slices.Equal(repo.Topics, []string{"cli", "git"}) || repo.Language != "Go" {
+		t.Errorf("topics = %v, language = %q", repo.Topics, repo.Language)
+	}
+
+	if _, err := github.NewClient(cfg).Repositories(context.Background(), "nobody"); err == nil {
+		t.Error("Repositories of an unknown owner succeeded")
+	}
+}
+
+func TestRepositoriesFallBackToREST(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{Name: "hello-world"})
+	server.AddToken("secret-token", "octocat")
+
+	if _, err := newClient(server).Repositories(context.Background(), "octocat"); err != nil {
+		t.Fatalf("Repositories without a token: %v", err)
+	}
+	if got := server.Requests(); len(got) != 1 || !strings.HasPrefix(got[0], "/users/octocat/repos") {
+		t.Errorf("requests without a token = %v, want REST only", got)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = "secret-token"
+	failures := []struct {
+		name    string
+		status  int
+		message string
+	}{
+		{"no GraphQL", http.StatusNotFound, "Not Found"},
+		{"bad gateway", http.StatusBadGateway, "Server Error"},
+		{"secondary rate limit", http.StatusForbidden, "You have exceeded a secondary rate limit."},
+		{"missing SSO authorization", http.StatusOK, "Resource protected by organization SAML enforcement."},
+	}
+	for _, failure := range failures {
+		t.Run(failure.name, func(t *testing.T) {
+			server.FailGraphQL(failure.status, failure.message)
+			repos, err := github.NewClient(cfg).Repositories(context.Background(), "octocat")
+			if err != nil {
+				t.Fatalf("Repositories: %v", err)
+			}
+			if len(repos) != 1 || repos[0].Name != "hello-world" {
+				t.Errorf("got %d repositories from REST, want hello-world", len(repos))
+			}
+		})
+	}
+}
+
+func TestGraphQLAndRESTListTheSameRepositories(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{Name: "hello"}, &models.Repository{Name: "secret", IsPrivate: true})
+	server.AddMember("acme", "octocat")
+	server.AddRepos("acme", &models.Repository{Name: "tool"}, &models.Repository{Name: "internal", IsPrivate: true})
+	server.AddRepos("hubot", &models.Repository{Name: "bot"}, &models.Repository{Name: "diary", IsPrivate: true})
+	server.AddToken("secret-token", "octocat")
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = "secret-token"
+	client := github.NewClient(cfg)
+
+	list := func(owner string) []string {
+		t.Helper()
+		repos, err := client.Repositories(context.Background(), owner)
+		if err != nil {
+			t.Fatalf("Repositories(%s): %v", owner, err)
+		}
+		names := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			names = append(names, repo.FullName)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	want := map[string][]string{
+		"octocat": {"octocat/hello", "octocat/secret"},
+		"acme":    {"acme/internal", "acme/tool"},
+		"hubot":   {"hubot/bot"},
+	}
+	graphQL := make(map[string][]string)
+	for owner := range want {
+		graphQL[owner] = list(owner)
+	}
+	if slices.ContainsFunc(server.Requests(), func(path string) bool { return path != "/graphql" }) {
+		t.Fatalf("requests = %v, want GraphQL only", server.Requests())
+	}
+
+	server.DisableGraphQL()
+	for owner, names := range want {
+		rest := list(owner)
+		if !slices.Equal(graphQL[owner], names) || !slices.Equal(rest, names) {
+			t.Errorf("%s: GraphQL listed %q and REST %q, want %q", owner, graphQL[owner], rest, names)
+		}
+	}
+	if !slices.ContainsFunc(server.Requests(), func(path string) bool { return strings.HasPrefix(path, "/orgs/acme/repos?") }) {
+		t.Errorf("requests = %v, want acme listed through /orgs/acme/repos", server.Requests())
+	}
+}
@@ -0,0 +1,253 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// repositoriesQuery lists an owner's repositories with the details REST
+// would need a request per repository for: languages, topics, the latest
+// release and the default branch's commit.
+const repositoriesQuery = `query($login: String!, $first: Int!, $cursor: String) {
+  repositoryOwner(login: $login) {
+    repositories(first: $first, after: $cursor, ownerAffiliations: OWNER, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        databaseId
+        name
+        nameWithOwner
+        owner { login __typename }
+        description
+        url
+        sshUrl
+        isFork
+        isPrivate
+        stargazerCount
+        forkCount
+        createdAt
+        updatedAt
+        pushedAt
+        diskUsage
+        hasWikiEnabled
+        primaryLanguage { name }
+        licenseInfo { key name spdxId }
+        parent { nameWithOwner }
+        defaultBranchRef { name target { oid } }
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+        languages(first: 10, orderBy: {field: SIZE, direction: DESC}) { totalSize edges { size node { name } } }
+        latestRelease { tagName name publishedAt }
+      }
+    }
+  }
+}`
+
+// graphQLRepository is a repository node of repositoriesQuery.
+type graphQLRepository struct {
+	DatabaseID    int64  `json:"databaseId"`
+	Name          string `json:"name"`
+	NameWithOwner string `json:"nameWithOwner"`
+	Owner         struct {
+		Login    string `json:"login"`
+		TypeName string `json:"__typename"`
+	} `json:"owner"`
+	Description     string    `json:"description"`
+	URL             string    `json:"url"`
+	SSHURL          string    `json:"sshUrl"`
+	IsFork          bool      `json:"isFork"`
+	IsPrivate       bool      `json:"isPrivate"`
+	StargazerCount  int       `json:"stargazerCount"`
+	ForkCount       int       `json:"forkCount"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	PushedAt        time.Time `json:"pushedAt"`
+	DiskUsage       int       `json:"diskUsage"`
+	HasWikiEnabled  bool      `json:"hasWikiEnabled"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	LicenseInfo *struct {
+		Key    string `json:"key"`
+		Name   string `json:"name"`
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	Parent *struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"parent"`
+	DefaultBranchRef *struct {
+		Name   string `json:"name"`
+		Target struct {
+			OID string `json:"oid"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	Languages struct {
+		TotalSize int `json:"totalSize"`
+		Edges     []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+	LatestRelease *struct {
+		TagName     string    `json:"tagName"`
+		Name        string    `json:"name"`
+		PublishedAt time.Time `json:"publishedAt"`
+	} `json:"latestRelease"`
+}
+
+type repositoriesResponse struct {
+	Data struct {
+		RepositoryOwner *struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []*graphQLRepository `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"repositoryOwner"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLRepositories lists username's repositories through the GraphQL API
+// in pages of 100, following cursors. GraphQL always requires a token, and
+// lists every repository the token can see, private ones included.
+func (c *Client) graphQLRepositories(ctx context.Context, username string) ([]*models.Repository, error) {
+	var repos []*models.Repository
+	var cursor *string
+
+	for {
+		var resp repositoriesResponse
+		variables := map[string]any{"login": username, "first": config.PerPage, "cursor": cursor}
+		if err := c.graphQL(ctx, repositoriesQuery, variables, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			messages := make([]string, len(resp.Errors))
+			for i, e := range resp.Errors {
+				messages[i] = e.Message
+			}
+			return nil, fmt.Errorf("GitHub GraphQL error: %s", strings.Join(messages, "; "))
+		}
+
+		owner := resp.Data.RepositoryOwner
+		if owner == nil {
+			return nil, fmt.Errorf("user or organization '%s' not found", username)
+		}
+
+		for _, node := range owner.Repositories.Nodes {
+			repos = append(repos, node.repository())
+		}
+
+		page := owner.Repositories.PageInfo
+		if !page.HasNextPage {
+			return repos, nil
+		}
+		cursor = &page.EndCursor
+	}
+}
+
+// graphQL posts query with variables and decodes the response into out.
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.graphQLURL(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// graphQLURL derives the GraphQL endpoint from the REST base URL. GitHub
+// Enterprise serves REST under /api/v3 and GraphQL under /api/graphql.
+func (c *Client) graphQLURL() string {
+	if base, ok := strings.CutSuffix(c.baseURL, "/api/v3"); ok {
+		return base + "/api/graphql"
+	}
+	return c.baseURL + "/graphql"
+}
+
+func (r *graphQLRepository) repository() *models.Repository {
+	repo := &models.Repository{
+		ID:          r.DatabaseID,
+		Name:        r.Name,
+		FullName:    r.NameWithOwner,
+		Owner:       models.Owner{Login: r.Owner.Login, Type: r.Owner.TypeName},
+		Description: r.Description,
+		CloneURL:    r.URL + ".git",
+		SSHURL:      r.SSHURL,
+		StarCount:   r.StargazerCount,
+		ForkCount:   r.ForkCount,
+		IsFork:      r.IsFork,
+		IsPrivate:   r.IsPrivate,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+		PushedAt:    r.PushedAt,
+		Size:        r.DiskUsage,
+		HasWiki:     r.HasWikiEnabled,
+	}
+
+	if r.PrimaryLanguage != nil {
+		repo.Language = r.PrimaryLanguage.Name
+	}
+	if r.LicenseInfo != nil {
+		repo.License = &models.License{Key: r.LicenseInfo.Key, Name: r.LicenseInfo.Name, SPDXID: r.LicenseInfo.SPDXID}
+	}
+	if r.LatestRelease != nil {
+		repo.LatestRelease = &models.Release{
+			TagName:     r.LatestRelease.TagName,
+			Name:        r.LatestRelease.Name,
+			PublishedAt: r.LatestRelease.PublishedAt,
+		}
+	}
+	if r.Parent != nil {
+		repo.Parent = &models.Repository{FullName: r.Parent.NameWithOwner}
+	}
+	if r.DefaultBranchRef != nil {
+		repo.DefaultBranch = r.DefaultBranchRef.Name
+		repo.HeadSHA = r.DefaultBranchRef.Target.OID
+	}
+	for _, node := range r.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, node.Topic.Name)
+	}
+	repo.LanguageTotal = r.Languages.TotalSize
+	for _, edge := range r.Languages.Edges {
+		repo.Languages = append(repo.Languages, models.LanguageSize{Name: edge.Node.Name, Bytes: edge.Size})
+	}
+
+	return repo
+}
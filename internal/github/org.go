@@ -0,0 +1,28 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Teams lists the teams of org that the token can see.
+func (c *Client) Teams(ctx context.Context, org string) ([]*Team, error) {
+	path := fmt.Sprintf("/orgs/%s/teams", org)
+	return getAllPages[*Team](ctx, c, path, "")
+}
+
+// TeamRepositories lists the repositories the team identified by slug has
+// access to in org.
+func (c *Client) TeamRepositories(ctx context.Context, org, slug string) ([]*models.Repository, error) {
+	path := fmt.Sprintf("/orgs/%s/teams/%s/repos", org, url.PathEscape(slug))
+	return getAllPages[*models.Repository](ctx, c, path, "")
+}
+
+// TopicRepositories lists org's repositories tagged with topic using the
+// search API, so the rest of the organization is never fetched.
+func (c *Client) TopicRepositories(ctx context.Context, org, topic string) ([]*models.Repository, error) {
+	return c.SearchRepositories(ctx, fmt.Sprintf("org:%s topic:%s", org, topic))
+}
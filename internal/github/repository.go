@@ -1,6 +1,12 @@
 package github
 
-import "github.com/chetanr25/mass-git-cloner/pkg/models"
+import (
+	"sort"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
 
 func FilterRepositories(repos []*models.Repository, filter models.FilterType) []*models.Repository {
 	if filter == models.FilterAll {
@@ -46,3 +52,61 @@ func CalculateStats(repos []*models.Repository) *models.RepositoryStats {
 
 	return stats
 }
+
+// CalculateListingStats totals listings of several owners, counting a
+// repository listed twice once, and breaks the totals down per owner in the
+// order the owners were given.
+func CalculateListingStats(listings []clone.Listing) *models.RepositoryStats {
+	var all []*models.Repository
+	var perOwner []*models.OwnerStats
+	seen := make(map[int64]bool)
+	for _, listing := range listings {
+		for _, repo := range listing.Repositories {
+			if !seen[repo.ID] {
+				seen[repo.ID] = true
+				all = append(all, repo)
+			}
+		}
+		perOwner = append(perOwner, &models.OwnerStats{
+			Owner: listing.Owner,
+			Stats: CalculateStats(listing.Repositories),
+			Err:   listing.Err,
+		})
+	}
+
+	stats := CalculateStats(all)
+	if len(listings) > 1 {
+		stats.PerOwner = perOwner
+	}
+	return stats
+}
+
+// SortRepositories orders repos in place by the given field. Ties keep their
+// existing relative order so repeated sorts are predictable.
+func SortRepositories(repos []*models.Repository, field models.SortField, descending bool) {
+	less := func(a, b *models.Repository) bool {
+		switch field {
+		case models.SortByName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case models.SortByStars:
+			return a.StarCount < b.StarCount
+		case models.SortByForks:
+			return a.ForkCount < b.ForkCount
+		case models.SortBySize:
+			return a.Size < b.Size
+		case models.SortByCreated:
+			return a.CreatedAt.Before(b.CreatedAt)
+		case models.SortByLanguage:
+			return strings.ToLower(a.Language) < strings.ToLower(b.Language)
+		default:
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+	}
+
+	sort.SliceStable(repos, func(i, j int) bool {
+		if descending {
+			return less(repos[j], repos[i])
+		}
+		return less(repos[i], repos[j])
+	})
+}
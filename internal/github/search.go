@@ -0,0 +1,314 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+const (
+	// searchResultCap is the most results GitHub returns for one query.
+	searchResultCap = 1000
+	// maxSearchWait bounds how long a search waits for its rate limit to
+	// reset; the search limit resets every minute.
+	maxSearchWait = 2 * time.Minute
+	// searchDateFormat is how created: ranges are written in queries.
+	searchDateFormat = "2006-01-02T15:04:05Z"
+)
+
+// firstRepositoryDate is earlier than any repository on GitHub, so it is
+// where splitting an open-ended query starts.
+var firstRepositoryDate = time.Date(2007, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+type searchResult struct {
+	TotalCount        int                  `json:"total_count"`
+	IncompleteResults bool                 `json:"incomplete_results"`
+	Items             []*models.Repository `json:"items"`
+}
+
+// SearchRepositories returns every repository matching a search query such
+// as "org:golang language:go pushed:>2024-01-01". GitHub returns at most
+// 1,000 results per query, so larger result sets are fetched by splitting
+// the query into creation date ranges.
+func (c *Client) SearchRepositories(ctx context.Context, query string) ([]*models.Repository, error) {
+	first, err := c.searchPage(ctx, query, 1)
+	if err != nil {
+		return nil, err
+	}
+	if first.TotalCount <= searchResultCap {
+		return c.searchAll(ctx, query, first)
+	}
+
+	base, from, to, err := splitCreated(query)
+	if err != nil {
+		return nil, fmt.Errorf("search matches %d repositories, more than the %d GitHub returns: %w",
+			first.TotalCount, searchResultCap, err)
+	}
+
+	seen := make(map[int64]bool)
+	var all []*models.Repository
+	if err := c.searchRange(ctx, base, from, to, seen, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// searchRange halves [from, to] until each part has no more than 1,000
+// results, collecting repositories not seen before.
+func (c *Client) searchRange(ctx context.Context, base string, from, to time.Time, seen map[int64]bool, all *[]*models.Repository) error {
+	query := fmt.Sprintf("%s created:%s..%s", base, from.Format(searchDateFormat), to.Format(searchDateFormat))
+
+	first, err := c.searchPage(ctx, query, 1)
+	if err != nil {
+		return err
+	}
+
+	if first.TotalCount > searchResultCap {
+		// created: has a resolution of one second, so a range that short
+		// can't be split any further.
+		if to.Sub(from) <= time.Second {
+			return fmt.Errorf("search matches %d repositories created between %s and %s, more than the %d GitHub returns",
+				first.TotalCount, from.Format(searchDateFormat), to.Format(searchDateFormat), searchResultCap)
+		}
+		mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+		if err := c.searchRange(ctx, base, from, mid, seen, all); err != nil {
+			return err
+		}
+		return c.searchRange(ctx, base, mid.Add(time.Second), to, seen, all)
+	}
+
+	repos, err := c.searchAll(ctx, query, first)
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if !seen[repo.ID] {
+			seen[repo.ID] = true
+			*all = append(*all, repo)
+		}
+	}
+	return nil
+}
+
+// searchAll fetches the pages of query after first, up to the result cap.
+func (c *Client) searchAll(ctx context.Context, query string, first *searchResult) ([]*models.Repository, error) {
+	all := first.Items
+	total := min(first.TotalCount, searchResultCap)
+
+	for page, items := 2, first.Items; len(items) == config.PerPage && len(all) < total; page++ {
+		result, err := c.searchPage(ctx, query, page)
+		if err != nil {
+			return nil, err
+		}
+		items = result.Items
+		all = append(all, items...)
+	}
+
+	return all, nil
+}
+
+// searchPage fetches one page of results, waiting out the search rate limit
+// instead of failing when it runs out.
+func (c *Client) searchPage(ctx context.Context, query string, page int) (*searchResult, error) {
+	q := url.QueryEscape(query)
+	endpoint := fmt.Sprintf("%s/search/repositories?q=%s&per_page=%d&page=%d", c.baseURL, q, config.PerPage, page)
+
+	for attempt := 1; ; attempt++ {
+		if err := c.waitForSearch(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		c.setHeaders(req)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Searches for several owners share the client, so a response that
+		// arrives late must not cancel the wait another one asked for.
+		resume, limited := searchResume(resp)
+		c.searchMu.Lock()
+		if resume.After(c.searchResumeAt) {
+			c.searchResumeAt = resume
+		}
+		c.searchMu.Unlock()
+
+		if limited && attempt < 3 && time.Until(resume) <= maxSearchWait {
+			resp.Body.Close()
+			continue
+		}
+
+		result, err := decodeSearch(resp)
+		resp.Body.Close()
+		return result, err
+	}
+}
+
+func decodeSearch(resp *http.Response) (*searchResult, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var result searchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// searchResume returns when the next search may be made and whether resp
+// was rejected by the rate limit. The zero time means right away.
+func searchResume(resp *http.Response) (time.Time, bool) {
+	limited := resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+
+	if limited {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return time.Now().Add(time.Duration(seconds) * time.Second), true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(reset, 0), limited
+}
+
+// waitForSearch sleeps until the search rate limit has reset, if the last
+// response said it was used up.
+func (c *Client) waitForSearch(ctx context.Context) error {
+	c.searchMu.Lock()
+	wait := time.Until(c.searchResumeAt)
+	c.searchMu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if wait > maxSearchWait {
+		return fmt.Errorf("GitHub search rate limit exceeded; resets at %s", c.searchResumeAt.Format(time.Kitchen))
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ScopeSearch limits query to owner's repositories unless it already names
+// a user, organization or repository.
+func ScopeSearch(query, owner string) string {
+	for _, term := range strings.Fields(query) {
+		qualifier, _, _ := strings.Cut(strings.TrimPrefix(term, "-"), ":")
+		switch qualifier {
+		case "user", "org", "repo":
+			return query
+		}
+	}
+	if owner == "" {
+		return query
+	}
+	return strings.TrimSpace(query + " user:" + owner)
+}
+
+// splitCreated removes any created: qualifier from query and returns the
+// date range to split, which defaults to all of GitHub's history.
+func splitCreated(query string) (base string, from, to time.Time, err error) {
+	from, to = firstRepositoryDate, time.Now().UTC().Truncate(time.Second)
+
+	var terms []string
+	for _, term := range strings.Fields(query) {
+		value, ok := strings.CutPrefix(term, "created:")
+		if !ok {
+			terms = append(terms, term)
+			continue
+		}
+		if from, to, err = parseCreated(value, from, to); err != nil {
+			return "", from, to, err
+		}
+	}
+
+	return strings.Join(terms, " "), from, to, nil
+}
+
+// parseCreated narrows [from, to] by a created: value such as
+// "2020-01-01..2021-06-30", ">=2022-01-01", "<2020-01-01T12:00:00Z" or a
+// single day.
+func parseCreated(value string, from, to time.Time) (time.Time, time.Time, error) {
+	invalid := fmt.Errorf("can't split created:%s into date ranges", value)
+
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		if lo != "*" {
+			start, _, err := parseSearchDate(lo)
+			if err != nil {
+				return from, to, invalid
+			}
+			from = start
+		}
+		if hi != "*" {
+			_, end, err := parseSearchDate(hi)
+			if err != nil {
+				return from, to, invalid
+			}
+			to = end
+		}
+		return from, to, nil
+	}
+
+	var err error
+	var start, end time.Time
+	switch {
+	case strings.HasPrefix(value, ">="):
+		start, _, err = parseSearchDate(value[2:])
+		from = start
+	case strings.HasPrefix(value, ">"):
+		_, end, err = parseSearchDate(value[1:])
+		from = end.Add(time.Second)
+	case strings.HasPrefix(value, "<="):
+		_, end, err = parseSearchDate(value[2:])
+		to = end
+	case strings.HasPrefix(value, "<"):
+		start, _, err = parseSearchDate(value[1:])
+		to = start.Add(-time.Second)
+	default:
+		from, to, err = parseSearchDate(value)
+	}
+	if err != nil {
+		return from, to, invalid
+	}
+	return from, to, nil
+}
+
+// parseSearchDate parses a date or date-time, returning the first and last
+// second it covers.
+func parseSearchDate(s string) (time.Time, time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return t, t, err
+	}
+	return t, t.Add(24*time.Hour - time.Second), nil
+}
@@ -12,3 +12,11 @@ type User struct {
 	Following   int    `json:"following"`
 	CreatedAt   string `json:"created_at"`
 }
+
+type Team struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Privacy     string `json:"privacy"`
+}
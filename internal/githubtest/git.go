@@ -0,0 +1,50 @@
+package githubtest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// BareRepo creates a bare repository named name.git under dir with a single
+// commit on main, and returns a file:// URL to use as a CloneURL. Tests are
+// skipped when git isn't installed.
+func BareRepo(t testing.TB, dir, name string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	bare := filepath.Join(dir, name+".git")
+	work := filepath.Join(t.TempDir(), name)
+
+	run(t, "", "init", "--quiet", "--bare", "--initial-branch=main", bare)
+	run(t, "", "init", "--quiet", "--initial-branch=main", work)
+
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# "+name+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run(t, work, "add", "README.md")
+	run(t, work, "-c", "user.name=gclone", "-c", "user.email=gclone@example.com", "commit", "--quiet", "-m", "Initial commit")
+	run(t, work, "push", "--quiet", bare, "main")
+
+	url := filepath.ToSlash(bare)
+	if !strings.HasPrefix(url, "/") {
+		url = "/" + url
+	}
+	return "file://" + url
+}
+
+func run(t testing.TB, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
@@ -0,0 +1,905 @@
+// Package githubtest provides an in-memory fake of the GitHub REST API and
+// local bare repositories to clone, so the full flow can be tested offline.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Server is a fake GitHub API. Point config.Config.APIBaseURL at URL.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	owners    map[string]string
+	repos     map[string][]*models.Repository
+	stars     map[string][]star
+	gists     map[string][]*models.Gist
+	teams     map[string][]*team
+	tokens    map[string]string
+	forbidden map[string]bool
+	remaining int
+	limit     int
+	search    searchLimit
+	graphQL   graphQLFailure
+	moved     map[string]int64
+	members   map[string]map[string]bool
+	nextID    int64
+	requests  []string
+}
+
+// NewServer starts a fake GitHub API that is closed when t finishes.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		owners:    make(map[string]string),
+		repos:     make(map[string][]*models.Repository),
+		stars:     make(map[string][]star),
+		gists:     make(map[string][]*models.Gist),
+		teams:     make(map[string][]*team),
+		tokens:    make(map[string]string),
+		forbidden: make(map[string]bool),
+		moved:     make(map[string]int64),
+		members:   make(map[string]map[string]bool),
+		remaining: -1,
+		limit:     60,
+		nextID:    1000,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{owner}", s.handleOwner)
+	mux.HandleFunc("GET /orgs/{owner}", s.handleOwner)
+	mux.HandleFunc("GET /users/{owner}/repos", s.handleRepos)
+	mux.HandleFunc("GET /orgs/{owner}/repos", s.handleOrgRepos)
+	mux.HandleFunc("GET /users/{owner}/starred", s.handleStarred)
+	mux.HandleFunc("GET /repos/{owner}/{name}", s.handleRepo)
+	mux.HandleFunc("GET /repositories/{id}", s.handleRepoByID)
+	mux.HandleFunc("GET /users/{owner}/gists", s.handleUserGists)
+	mux.HandleFunc("GET /gists", s.handleOwnGists)
+	mux.HandleFunc("GET /user", s.handleAuthenticatedUser)
+	mux.HandleFunc("GET /user/repos", s.handleOwnRepos)
+	mux.HandleFunc("GET /orgs/{org}/teams", s.handleTeams)
+	mux.HandleFunc("GET /orgs/{org}/teams/{team}/repos", s.handleTeamRepos)
+	mux.HandleFunc("GET /search/repositories", s.handleSearch)
+	mux.HandleFunc("POST /graphql", s.handleGraphQL)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// AddUser registers a user account.
+func (s *Server) AddUser(login string) {
+	s.addOwner(login, "User")
+}
+
+// AddOrg registers an organization.
+func (s *Server) AddOrg(login string) {
+	s.addOwner(login, "Organization")
+}
+
+func (s *Server) addOwner(login, ownerType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners[strings.ToLower(login)] = ownerType
+}
+
+// AddRepos adds repositories to owner, filling in any ID, full name and
+// owner left empty. The owner is registered as a user if it doesn't exist.
+func (s *Server) AddRepos(owner string, repos ...*models.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(owner)
+	if _, ok := s.owners[key]; !ok {
+		s.owners[key] = "User"
+	}
+
+	for _, repo := range repos {
+		if repo.ID == 0 {
+			s.nextID++
+			repo.ID = s.nextID
+		}
+		if repo.FullName == "" {
+			repo.FullName = owner + "/" + repo.Name
+		}
+		if repo.Owner.Login == "" {
+			repo.Owner = models.Owner{Login: owner, Type: s.owners[key]}
+		}
+		if repo.CloneURL == "" {
+			repo.CloneURL = "https://github.com/" + repo.FullName + ".git"
+		}
+		s.repos[key] = append(s.repos[key], repo)
+	}
+}
+
+type star struct {
+	StarredAt time.Time          `json:"starred_at"`
+	Repo      *models.Repository `json:"repo"`
+}
+
+// Star records user starring repos at starredAt. The repositories should
+// already belong to their owners via AddRepos.
+func (s *Server) Star(user string, starredAt time.Time, repos ...*models.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(user)
+	if _, ok := s.owners[key]; !ok {
+		s.owners[key] = "User"
+	}
+	for _, repo := range repos {
+		s.stars[key] = append(s.stars[key], star{StarredAt: starredAt, Repo: repo})
+	}
+}
+
+// AddToken makes token authenticate as login.
+func (s *Server) AddToken(token, login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = login
+}
+
+// AddMember makes login a member of org, who can see its private
+// repositories. The organization is registered if needed.
+func (s *Server) AddMember(org, login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(org)
+	if _, ok := s.owners[key]; !ok {
+		s.owners[key] = "Organization"
+	}
+	if s.members[key] == nil {
+		s.members[key] = make(map[string]bool)
+	}
+	s.members[key][strings.ToLower(login)] = true
+}
+
+// AddGists adds gists to owner, filling in the owner and pull URL if empty.
+func (s *Server) AddGists(owner string, gists ...*models.Gist) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(owner)
+	if _, ok := s.owners[key]; !ok {
+		s.owners[key] = "User"
+	}
+	for _, gist := range gists {
+		if gist.Owner.Login == "" {
+			gist.Owner = models.Owner{Login: owner, Type: s.owners[key]}
+		}
+		if gist.GitPullURL == "" {
+			gist.GitPullURL = "https://gist.github.com/" + gist.ID + ".git"
+		}
+		s.gists[key] = append(s.gists[key], gist)
+	}
+}
+
+type team struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	repos []*models.Repository
+}
+
+// AddTeam adds a team to org with access to repos, which should already
+// belong to org via AddRepos. The organization is registered if needed.
+func (s *Server) AddTeam(org, slug, name string, repos ...*models.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(org)
+	if _, ok := s.owners[key]; !ok {
+		s.owners[key] = "Organization"
+	}
+	s.nextID++
+	s.teams[key] = append(s.teams[key], &team{ID: s.nextID, Name: name, Slug: slug, repos: repos})
+}
+
+// login returns who the request's bearer token authenticates as.
+func (s *Server) login(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, ok := s.tokens[token]
+	return login, ok
+}
+
+// RemoveRepo deletes owner/name, as if it was deleted upstream.
+func (s *Server) RemoveRepo(owner, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(owner)
+	repos := s.repos[key]
+	for i, repo := range repos {
+		if strings.EqualFold(repo.Name, name) {
+			s.repos[key] = append(repos[:i:i], repos[i+1:]...)
+			return
+		}
+	}
+}
+
+// Transfer moves owner/name to newOwner, as if it was transferred upstream.
+// Requests for the old name are redirected, like GitHub does.
+func (s *Server) Transfer(owner, name, newOwner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, newKey := strings.ToLower(owner), strings.ToLower(newOwner)
+	if _, ok := s.owners[newKey]; !ok {
+		s.owners[newKey] = "User"
+	}
+
+	repos := s.repos[key]
+	for i, repo := range repos {
+		if !strings.EqualFold(repo.Name, name) {
+			continue
+		}
+		s.repos[key] = append(repos[:i:i], repos[i+1:]...)
+		s.moved[strings.ToLower(repo.FullName)] = repo.ID
+
+		repo.FullName = newOwner + "/" + repo.Name
+		repo.Owner = models.Owner{Login: newOwner, Type: s.owners[newKey]}
+		repo.CloneURL = "https://github.com/" + repo.FullName + ".git"
+		s.repos[newKey] = append(s.repos[newKey], repo)
+		return
+	}
+}
+
+// Forbid makes every request whose path starts with prefix fail with 403.
+func (s *Server) Forbid(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forbidden[prefix] = true
+}
+
+// SetRateLimit allows remaining more requests before the server answers
+// 403 with exhausted rate-limit headers, like GitHub does. A negative value
+// disables the limit.
+func (s *Server) SetRateLimit(remaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remaining = remaining
+}
+
+// searchLimit is the separate, per-minute rate limit of the search API.
+type searchLimit struct {
+	limit     int
+	remaining int
+	window    time.Duration
+	reset     time.Time
+}
+
+// SetSearchRateLimit allows limit searches per window, after which searches
+// fail with 403 until the window resets, like GitHub's search rate limit.
+func (s *Server) SetSearchRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.search = searchLimit{limit: limit, remaining: limit, window: window, reset: time.Now().Add(window)}
+}
+
+// takeSearch uses up one search and sets the search rate-limit headers. It
+// reports false when the limit is exhausted.
+func (s *Server) takeSearch(w http.ResponseWriter) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := &s.search
+	if l.limit == 0 {
+		return true
+	}
+
+	now := time.Now()
+	if !now.Before(l.reset) {
+		l.remaining = l.limit
+		l.reset = now.Add(l.window)
+	}
+
+	ok := l.remaining > 0
+	if ok {
+		l.remaining--
+	}
+
+	// Round the reset up so clients waiting for it never retry early.
+	reset := l.reset.Unix()
+	if l.reset.After(time.Unix(reset, 0)) {
+		reset++
+	}
+
+	w.Header().Set("X-RateLimit-Resource", "search")
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(l.limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(l.remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	return ok
+}
+
+// graphQLFailure is how /graphql answers instead of running the query.
+// A zero status answers normally; http.StatusOK answers with an errors
+// payload.
+type graphQLFailure struct {
+	status  int
+	message string
+}
+
+// DisableGraphQL makes /graphql answer 404, like servers without the
+// GraphQL API.
+func (s *Server) DisableGraphQL() {
+	s.FailGraphQL(http.StatusNotFound, "Not Found")
+}
+
+// FailGraphQL makes /graphql fail with status and message. With
+// http.StatusOK it answers like GitHub does for query errors, such as a
+// token missing SAML SSO authorization: no data and message in errors.
+func (s *Server) FailGraphQL(status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.graphQL = graphQLFailure{status: status, message: message}
+}
+
+// Requests returns the paths (with query) requested so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.URL.RequestURI())
+
+		forbidden := false
+		for prefix := range s.forbidden {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				forbidden = true
+			}
+		}
+
+		limited := s.remaining == 0
+		if s.remaining > 0 {
+			s.remaining--
+		}
+		remaining := s.remaining
+		if remaining < 0 {
+			remaining = s.limit
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+
+		switch {
+		case limited:
+			writeError(w, http.StatusForbidden, "API rate limit exceeded")
+		case forbidden:
+			writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (s *Server) handleOwner(w http.ResponseWriter, r *http.Request) {
+	login := r.PathValue("owner")
+
+	s.mu.Lock()
+	ownerType, ok := s.owners[strings.ToLower(login)]
+	s.mu.Unlock()
+
+	if !ok || (strings.HasPrefix(r.URL.Path, "/orgs/") && ownerType != "Organization") {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, map[string]any{"login": login, "type": ownerType})
+}
+
+func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("owner"))
+
+	s.mu.Lock()
+	_, ok := s.owners[key]
+	repos := append([]*models.Repository(nil), s.repos[key]...)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	// Like GitHub, this lists only public repositories, even for their
+	// owner.
+	writePage(w, r, s.URL, listed(repos, false))
+}
+
+// handleOrgRepos lists an organization's repositories like type=all: its
+// private ones too when the request is authenticated as a member.
+func (s *Server) handleOrgRepos(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("owner"))
+	login, _ := s.login(r)
+
+	s.mu.Lock()
+	ownerType := s.owners[key]
+	var repos []*models.Repository
+	for _, repo := range s.repos[key] {
+		if s.visible(repo, login) {
+			repos = append(repos, repo)
+		}
+	}
+	s.mu.Unlock()
+
+	if ownerType != "Organization" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writePage(w, r, s.URL, listed(repos, true))
+}
+
+// handleOwnRepos lists the authenticated user's own repositories, private
+// ones included. Only affiliation=owner is supported.
+func (s *Server) handleOwnRepos(w http.ResponseWriter, r *http.Request) {
+	login, ok := s.login(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Requires authentication")
+		return
+	}
+
+	s.mu.Lock()
+	repos := append([]*models.Repository(nil), s.repos[strings.ToLower(login)]...)
+	s.mu.Unlock()
+
+	writePage(w, r, s.URL, listed(repos, true))
+}
+
+// listed drops what GitHub leaves out of a repository listing: private
+// repositories unless asked for, and a fork's parent, which only
+// GET /repos/{owner}/{repo} returns.
+func listed(repos []*models.Repository, private bool) []*models.Repository {
+	out := make([]*models.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if repo.IsPrivate && !private {
+			continue
+		}
+		copied := *repo
+		copied.Parent = nil
+		out = append(out, &copied)
+	}
+	return out
+}
+
+// visible reports whether a request authenticated as login may see repo.
+// Private repositories answer 404 to everyone but their owner and, for an
+// organization's, its members. The caller holds s.mu.
+func (s *Server) visible(repo *models.Repository, login string) bool {
+	return !repo.IsPrivate || strings.EqualFold(repo.Owner.Login, login) ||
+		s.members[strings.ToLower(repo.Owner.Login)][strings.ToLower(login)]
+}
+
+// handleStarred returns bare repositories unless the star+json media type
+// is requested, in which case each item wraps the repository with
+// starred_at, like the real API.
+func (s *Server) handleStarred(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("owner"))
+
+	s.mu.Lock()
+	_, ok := s.owners[key]
+	stars := append([]star(nil), s.stars[key]...)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	if r.Header.Get("Accept") == "application/vnd.github.star+json" {
+		writePage(w, r, s.URL, stars)
+		return
+	}
+
+	repos := make([]*models.Repository, len(stars))
+	for i, st := range stars {
+		repos[i] = st.Repo
+	}
+	writePage(w, r, s.URL, repos)
+}
+
+// writePage serves one page of items with a Link header, honouring per_page
+// and page like the real API.
+func writePage[T any](w http.ResponseWriter, r *http.Request, baseURL string, items []T) {
+	perPage := queryInt(r, "per_page", 30)
+	page := queryInt(r, "page", 1)
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	if end < len(items) {
+		next := *r.URL
+		q := next.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s%s>; rel=\"next\"", baseURL, next.RequestURI()))
+	}
+
+	writeJSON(w, items[start:end])
+}
+
+func (s *Server) handleAuthenticatedUser(w http.ResponseWriter, r *http.Request) {
+	login, ok := s.login(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Requires authentication")
+		return
+	}
+	writeJSON(w, map[string]any{"login": login, "type": "User"})
+}
+
+// handleUserGists lists only public gists, as GitHub does for anyone.
+func (s *Server) handleUserGists(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("owner"))
+
+	s.mu.Lock()
+	_, ok := s.owners[key]
+	var gists []*models.Gist
+	for _, gist := range s.gists[key] {
+		if gist.Public {
+			gists = append(gists, gist)
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writePage(w, r, s.URL, gists)
+}
+
+// handleOwnGists lists the authenticated user's gists, secret ones included.
+func (s *Server) handleOwnGists(w http.ResponseWriter, r *http.Request) {
+	login, ok := s.login(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Requires authentication")
+		return
+	}
+
+	s.mu.Lock()
+	gists := append([]*models.Gist(nil), s.gists[strings.ToLower(login)]...)
+	s.mu.Unlock()
+
+	writePage(w, r, s.URL, gists)
+}
+
+func (s *Server) handleRepo(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("owner"))
+	name := r.PathValue("name")
+	login, _ := s.login(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, repo := range s.repos[key] {
+		if strings.EqualFold(repo.Name, name) && s.visible(repo, login) {
+			writeJSON(w, repo)
+			return
+		}
+	}
+
+	if id, ok := s.moved[key+"/"+strings.ToLower(name)]; ok {
+		http.Redirect(w, r, fmt.Sprintf("/repositories/%d", id), http.StatusMovedPermanently)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) handleRepoByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	login, _ := s.login(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, repos := range s.repos {
+		for _, repo := range repos {
+			if repo.ID == id && s.visible(repo, login) {
+				writeJSON(w, repo)
+				return
+			}
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) handleTeams(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("org"))
+
+	s.mu.Lock()
+	ownerType := s.owners[key]
+	teams := append([]*team(nil), s.teams[key]...)
+	s.mu.Unlock()
+
+	if ownerType != "Organization" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writePage(w, r, s.URL, teams)
+}
+
+func (s *Server) handleTeamRepos(w http.ResponseWriter, r *http.Request) {
+	key := strings.ToLower(r.PathValue("org"))
+	slug := r.PathValue("team")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.teams[key] {
+		if strings.EqualFold(t.Slug, slug) {
+			writePage(w, r, s.URL, t.repos)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// handleSearch answers repository searches over every owner's
+// repositories. It understands the org:, user:, topic:, language: and
+// created:from..to qualifiers and, like GitHub, only serves the first 1,000
+// results.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if !s.takeSearch(w) {
+		writeError(w, http.StatusForbidden, "API rate limit exceeded")
+		return
+	}
+
+	var owners, topics []string
+	var language string
+	var from, to time.Time
+	for _, term := range strings.Fields(r.URL.Query().Get("q")) {
+		qualifier, value, _ := strings.Cut(term, ":")
+		switch qualifier {
+		case "org", "user":
+			owners = append(owners, strings.ToLower(value))
+		case "topic":
+			topics = append(topics, strings.ToLower(value))
+		case "language":
+			language = value
+		case "created":
+			lo, hi, _ := strings.Cut(value, "..")
+			from, _ = time.Parse(time.RFC3339, lo)
+			to, _ = time.Parse(time.RFC3339, hi)
+		}
+	}
+
+	s.mu.Lock()
+	var matches []*models.Repository
+	for owner, repos := range s.repos {
+		if len(owners) > 0 && !slices.Contains(owners, owner) {
+			continue
+		}
+		for _, repo := range repos {
+			switch {
+			case !hasTopics(repo, topics):
+			case language != "" && !strings.EqualFold(repo.Language, language):
+			case !from.IsZero() && repo.CreatedAt.Before(from):
+			case !to.IsZero() && repo.CreatedAt.After(to):
+			default:
+				matches = append(matches, repo)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+
+	perPage := queryInt(r, "per_page", 30)
+	page := queryInt(r, "page", 1)
+	if (page-1)*perPage >= 1000 {
+		writeError(w, http.StatusUnprocessableEntity, "Only the first 1000 search results are available")
+		return
+	}
+	start := min((page-1)*perPage, len(matches))
+	end := min(start+perPage, len(matches), 1000)
+
+	writeJSON(w, map[string]any{
+		"total_count":        len(matches),
+		"incomplete_results": false,
+		"items":              matches[start:end],
+	})
+}
+
+func hasTopics(repo *models.Repository, topics []string) bool {
+	for _, topic := range topics {
+		if !slices.ContainsFunc(repo.Topics, func(t string) bool { return strings.EqualFold(t, topic) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// handleGraphQL answers the repository listing query of the client, paging
+// by the first and cursor variables. The query text itself isn't parsed.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	failure := s.graphQL
+	s.mu.Unlock()
+	switch failure.status {
+	case 0:
+	case http.StatusOK:
+		writeJSON(w, map[string]any{
+			"data":   nil,
+			"errors": []map[string]any{{"type": "FORBIDDEN", "message": failure.message}},
+		})
+		return
+	default:
+		writeError(w, failure.status, failure.message)
+		return
+	}
+
+	login, ok := s.login(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "This endpoint requires you to be authenticated.")
+		return
+	}
+
+	var body struct {
+		Variables struct {
+			Login  string  `json:"login"`
+			First  int     `json:"first"`
+			Cursor *string `json:"cursor"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	key := strings.ToLower(body.Variables.Login)
+	s.mu.Lock()
+	_, ok = s.owners[key]
+	var repos []*models.Repository
+	for _, repo := range s.repos[key] {
+		if s.visible(repo, login) {
+			repos = append(repos, repo)
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, map[string]any{
+			"data":   map[string]any{"repositoryOwner": nil},
+			"errors": []map[string]any{{"type": "NOT_FOUND", "message": "Could not resolve to a RepositoryOwner with the login of '" + body.Variables.Login + "'."}},
+		})
+		return
+	}
+
+	start := 0
+	if body.Variables.Cursor != nil {
+		start, _ = strconv.Atoi(*body.Variables.Cursor)
+	}
+	start = min(start, len(repos))
+	end := min(start+max(body.Variables.First, 1), len(repos))
+
+	nodes := make([]map[string]any, 0, end-start)
+	for _, repo := range repos[start:end] {
+		nodes = append(nodes, graphQLNode(repo))
+	}
+
+	writeJSON(w, map[string]any{
+		"data": map[string]any{
+			"repositoryOwner": map[string]any{
+				"repositories": map[string]any{
+					"pageInfo": map[string]any{"hasNextPage": end < len(repos), "endCursor": strconv.Itoa(end)},
+					"nodes":    nodes,
+				},
+			},
+		},
+	})
+}
+
+// graphQLNode renders repo the way the GraphQL API does.
+func graphQLNode(repo *models.Repository) map[string]any {
+	node := map[string]any{
+		"databaseId":     repo.ID,
+		"name":           repo.Name,
+		"nameWithOwner":  repo.FullName,
+		"owner":          map[string]any{"login": repo.Owner.Login, "__typename": repo.Owner.Type},
+		"description":    repo.Description,
+		"url":            strings.TrimSuffix(repo.CloneURL, ".git"),
+		"sshUrl":         repo.SSHURL,
+		"isFork":         repo.IsFork,
+		"isPrivate":      repo.IsPrivate,
+		"stargazerCount": repo.StarCount,
+		"forkCount":      repo.ForkCount,
+		"createdAt":      repo.CreatedAt,
+		"updatedAt":      repo.UpdatedAt,
+		"pushedAt":       repo.PushedAt,
+		"diskUsage":      repo.Size,
+		"hasWikiEnabled": repo.HasWiki,
+	}
+
+	if repo.Language != "" {
+		node["primaryLanguage"] = map[string]any{"name": repo.Language}
+	}
+	if repo.DefaultBranch != "" {
+		node["defaultBranchRef"] = map[string]any{"name": repo.DefaultBranch, "target": map[string]any{"oid": repo.HeadSHA}}
+	}
+	if repo.LatestRelease != nil {
+		node["latestRelease"] = map[string]any{
+			"tagName":     repo.LatestRelease.TagName,
+			"name":        repo.LatestRelease.Name,
+			"publishedAt": repo.LatestRelease.PublishedAt,
+		}
+	}
+
+	topics := make([]map[string]any, 0, len(repo.Topics))
+	for _, topic := range repo.Topics {
+		topics = append(topics, map[string]any{"topic": map[string]any{"name": topic}})
+	}
+	node["repositoryTopics"] = map[string]any{"nodes": topics}
+
+	// Like GitHub, only the largest ten languages are returned; totalSize
+	// covers all of them.
+	total := repo.LanguageTotal
+	languages := make([]map[string]any, 0, len(repo.Languages))
+	for i, language := range repo.Languages {
+		if repo.LanguageTotal == 0 {
+			total += language.Bytes
+		}
+		if i < 10 {
+			languages = append(languages, map[string]any{"size": language.Bytes, "node": map[string]any{"name": language.Name}})
+		}
+	}
+	node["languages"] = map[string]any{"totalSize": total, "edges": languages}
+
+	return node
+}
+
+func queryInt(r *http.Request, name string, fallback int) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
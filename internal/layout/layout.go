@@ -0,0 +1,230 @@
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Default keeps the original <BaseDir>/<username>/<repo> layout.
+const Default = "default"
+
+var presets = map[string]string{
+	Default:    "{{.User}}/{{.Name}}",
+	"ghq":      "{{.Host}}/{{.Owner}}/{{.Name}}",
+	"owner":    "{{.Owner}}/{{.Name}}",
+	"language": "{{.Language}}/{{.Name}}",
+}
+
+// Data is what a layout template can refer to for each repository.
+type Data struct {
+	Host     string
+	Owner    string
+	User     string
+	Name     string
+	FullName string
+	Language string
+}
+
+type Layout struct {
+	spec string
+	tmpl *template.Template
+}
+
+// Parse accepts either a preset name (default, ghq, owner, language) or a
+// text/template such as "{{.Host}}/{{.Owner}}/{{.Name}}".
+func Parse(spec string) (*Layout, error) {
+	if spec == "" {
+		spec = Default
+	}
+
+	text := spec
+	if preset, ok := presets[spec]; ok {
+		text = preset
+	}
+
+	tmpl, err := template.New("layout").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout template %q: %w", spec, err)
+	}
+
+	return &Layout{spec: spec, tmpl: tmpl}, nil
+}
+
+func (l *Layout) String() string {
+	return l.spec
+}
+
+// Path resolves where repo should live under baseDir. user is the owner the
+// run was started for, which differs from the repository owner for sources
+// like starred repositories.
+func (l *Layout) Path(baseDir, user string, repo *models.Repository) (string, error) {
+	var buf bytes.Buffer
+	if err := l.tmpl.Execute(&buf, newData(user, repo)); err != nil {
+		return "", fmt.Errorf("failed to apply layout to %s: %w", repo.DisplayName(), err)
+	}
+
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(buf.String())))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("layout %q maps %s outside the target directory: %q", l.spec, repo.DisplayName(), rel)
+	}
+
+	return filepath.Join(baseDir, rel), nil
+}
+
+func newData(user string, repo *models.Repository) Data {
+	if repo.RequestedOwner != "" {
+		user = repo.RequestedOwner
+	}
+
+	owner := repo.Owner.Login
+	if owner == "" {
+		if i := strings.Index(repo.FullName, "/"); i > 0 {
+			owner = repo.FullName[:i]
+		} else {
+			owner = user
+		}
+	}
+
+	host := "github.com"
+	if u, err := url.Parse(repo.CloneURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	language := repo.Language
+	if language == "" {
+		language = "unknown"
+	}
+
+	// Gists keep to a gists/ folder wherever the layout puts the name.
+	name := repo.Name
+	if repo.Gist != nil {
+		name = "gists/" + name
+	}
+
+	return Data{
+		Host:     host,
+		Owner:    owner,
+		User:     user,
+		Name:     name,
+		FullName: repo.FullName,
+		Language: language,
+	}
+}
+
+// Collision is a target path claimed by more than one repository.
+type Collision struct {
+	Path         string
+	Repositories []*models.Repository
+}
+
+type Plan struct {
+	BaseDir    string
+	Paths      map[int64]string
+	Collisions []Collision
+}
+
+// Plan resolves every repository's path and reports collisions. Paths are
+// compared case-insensitively because macOS and Windows filesystems are.
+func (l *Layout) Plan(baseDir, user string, repos []*models.Repository) (*Plan, error) {
+	plan := &Plan{BaseDir: baseDir, Paths: make(map[int64]string, len(repos))}
+	claimed := make(map[string][]*models.Repository)
+	var order []string
+
+	for _, repo := range repos {
+		path, err := l.Path(baseDir, user, repo)
+		if err != nil {
+			return nil, err
+		}
+		plan.Paths[repo.ID] = path
+
+		key := strings.ToLower(path)
+		if _, ok := claimed[key]; !ok {
+			order = append(order, key)
+		}
+		claimed[key] = append(claimed[key], repo)
+	}
+
+	for _, key := range order {
+		if owners := claimed[key]; len(owners) > 1 {
+			plan.Collisions = append(plan.Collisions, Collision{
+				Path:         plan.Paths[owners[0].ID],
+				Repositories: owners,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+func (p *Plan) CollisionError() error {
+	if len(p.Collisions) == 0 {
+		return nil
+	}
+
+	var s strings.Builder
+	s.WriteString("layout maps several repositories to the same path:")
+	for _, c := range p.Collisions {
+		names := make([]string, len(c.Repositories))
+		for i, repo := range c.Repositories {
+			names[i] = repo.DisplayName()
+		}
+		s.WriteString(fmt.Sprintf("\n  %s <- %s", c.Path, strings.Join(names, ", ")))
+	}
+
+	return fmt.Errorf("%s", s.String())
+}
+
+// Tree renders paths below baseDir as an indented directory tree.
+func Tree(baseDir string, paths []string) string {
+	type node struct {
+		children map[string]*node
+	}
+	root := &node{children: map[string]*node{}}
+
+	for _, path := range paths {
+		rel, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			rel = path
+		}
+		current := root
+		for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+			child, ok := current.children[part]
+			if !ok {
+				child = &node{children: map[string]*node{}}
+				current.children[part] = child
+			}
+			current = child
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString(baseDir + "\n")
+
+	var walk func(n *node, prefix string)
+	walk = func(n *node, prefix string) {
+		names := make([]string, 0, len(n.children))
+		for name := range n.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for i, name := range names {
+			branch, next := "├── ", "│   "
+			if i == len(names)-1 {
+				branch, next = "└── ", "    "
+			}
+			s.WriteString(prefix + branch + name + "\n")
+			walk(n.children[name], prefix+next)
+		}
+	}
+	walk(root, "")
+
+	return s.String()
+}
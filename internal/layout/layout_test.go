@@ -0,0 +1,172 @@
+package layout_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func TestPath(t *testing.T) {
+	repo := &models.Repository{
+		Name:     "hello",
+		FullName: "octocat/hello",
+		Owner:    models.Owner{Login: "octocat"},
+		CloneURL: "https://github.example.com/octocat/hello.git",
+		Language: "Go",
+	}
+
+	tests := []struct {
+		spec string
+		repo *models.Repository
+		want string
+	}{
+		{"", repo, "src/hubot/hello"},
+		{"default", repo, "src/hubot/hello"},
+		{"ghq", repo, "src/github.example.com/octocat/hello"},
+		{"owner", repo, "src/octocat/hello"},
+		{"language", repo, "src/Go/hello"},
+		{"language", &models.Repository{Name: "notes", FullName: "octocat/notes"}, "src/unknown/notes"},
+		{"{{.Owner}}/{{.FullName}}", repo, "src/octocat/octocat/hello"},
+		{"default", &models.Repository{Name: "hello", FullName: "octocat/hello", RequestedOwner: "octocat"}, "src/octocat/hello"},
+		{"owner", &models.Repository{Name: "abc123", Gist: &models.Gist{ID: "abc123"}, Owner: models.Owner{Login: "octocat"}}, "src/octocat/gists/abc123"},
+	}
+
+	for _, tt := range tests {
+		l, err := layout.Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		got, err := l.Path("src", "hubot", tt.repo)
+		if err != nil {
+			t.Errorf("%q: Path(%s): %v", tt.spec, tt.repo.Name, err)
+			continue
+		}
+		if want := filepath.FromSlash(tt.want); got != want {
+			t.Errorf("%q: Path(%s) = %q, want %q", tt.spec, tt.repo.Name, got, want)
+		}
+	}
+}
+
+func TestPathStaysInsideTarget(t *testing.T) {
+	tests := []struct {
+		spec string
+		name string
+	}{
+		{"{{.Name}}", ".."},
+		{"{{.Name}}", "../escape"},
+		{"{{.Owner}}/../../{{.Name}}", "hello"},
+		{"/{{.Name}}", "hello"},
+		{"{{.Name}}", "."},
+		{" ", "hello"},
+	}
+
+	for _, tt := range tests {
+		l, err := layout.Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.spec, err)
+		}
+		repo := &models.Repository{Name: tt.name, FullName: "octocat/" + tt.name, Owner: models.Owner{Login: "octocat"}}
+		if path, err := l.Path("src", "octocat", repo); err == nil {
+			t.Errorf("%q with name %q = %q, want an error", tt.spec, tt.name, path)
+		} else if !strings.Contains(err.Error(), "outside the target directory") {
+			t.Errorf("%q with name %q: error = %v", tt.spec, tt.name, err)
+		}
+	}
+}
+
+func TestParseRejectsInvalidTemplates(t *testing.T) {
+	for _, spec := range []string{"{{.Name", "{{.Nope}}/{{.Name}}"} {
+		l, err := layout.Parse(spec)
+		if err == nil {
+			_, err = l.Path("src", "octocat", &models.Repository{Name: "hello"})
+		}
+		if err == nil {
+			t.Errorf("layout %q was accepted", spec)
+		}
+	}
+}
+
+func TestPlanCollisions(t *testing.T) {
+	repo := func(id int64, owner, name string) *models.Repository {
+		return &models.Repository{ID: id, Name: name, FullName: owner + "/" + name, Owner: models.Owner{Login: owner}}
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		repos []*models.Repository
+		want  [][]int64
+	}{
+		{
+			name:  "distinct paths",
+			spec:  "owner",
+			repos: []*models.Repository{repo(1, "octocat", "hello"), repo(2, "hubot", "hello")},
+		},
+		{
+			name:  "same name from two owners",
+			spec:  "{{.Name}}",
+			repos: []*models.Repository{repo(1, "octocat", "hello"), repo(2, "hubot", "hello"), repo(3, "hubot", "other")},
+			want:  [][]int64{{1, 2}},
+		},
+		{
+			name:  "names differing only in case",
+			spec:  "owner",
+			repos: []*models.Repository{repo(1, "octocat", "Hello"), repo(2, "Octocat", "hello")},
+			want:  [][]int64{{1, 2}},
+		},
+		{
+			name:  "several collisions in listing order",
+			spec:  "{{.Name}}",
+			repos: []*models.Repository{repo(1, "a", "x"), repo(2, "a", "y"), repo(3, "b", "y"), repo(4, "b", "x"), repo(5, "c", "x")},
+			want:  [][]int64{{1, 4, 5}, {2, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := layout.Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			plan, err := l.Plan("src", "octocat", tt.repos)
+			if err != nil {
+				t.Fatalf("Plan: %v", err)
+			}
+			if len(plan.Paths) != len(tt.repos) {
+				t.Errorf("got %d paths, want %d", len(plan.Paths), len(tt.repos))
+			}
+
+			var got [][]int64
+			for _, c := range plan.Collisions {
+				var ids []int64
+				for _, r := range c.Repositories {
+					ids = append(ids, r.ID)
+				}
+				got = append(got, ids)
+				if c.Path != plan.Paths[ids[0]] {
+					t.Errorf("collision path %q, want the first repository's %q", c.Path, plan.Paths[ids[0]])
+				}
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]int64]) {
+				t.Errorf("collisions = %v, want %v", got, tt.want)
+			}
+			if (plan.CollisionError() != nil) != (len(tt.want) > 0) {
+				t.Errorf("CollisionError() = %v", plan.CollisionError())
+			}
+		})
+	}
+}
+
+func TestPlanPropagatesPathErrors(t *testing.T) {
+	l, err := layout.Parse("{{.Name}}")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := l.Plan("src", "octocat", []*models.Repository{{ID: 1, Name: ".."}}); err == nil {
+		t.Error("Plan accepted a path outside the target directory")
+	}
+}
@@ -0,0 +1,60 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// processLock serialises manifest updates between goroutines; the file lock
+// only guards against other gclone processes.
+var processLock sync.Mutex
+
+// Lock takes an exclusive lock on the manifest for baseDir, blocking until
+// any other run releases it. Call the returned function to unlock.
+func Lock(baseDir string) (func(), error) {
+	if err := os.MkdirAll(filepath.Join(baseDir, Dir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	processLock.Lock()
+
+	f, err := os.OpenFile(filepath.Join(baseDir, Dir, "state.lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		processLock.Unlock()
+		return nil, fmt.Errorf("failed to open manifest lock: %w", err)
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close()
+		processLock.Unlock()
+		return nil, fmt.Errorf("failed to lock manifest: %w", err)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+		processLock.Unlock()
+	}, nil
+}
+
+// Update loads the manifest under lock, applies fn and saves the result.
+func Update(baseDir string, fn func(*Manifest) error) error {
+	unlock, err := Lock(baseDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m, err := Load(baseDir)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(m); err != nil {
+		return err
+	}
+
+	return m.Save()
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || openbsd || dragonfly || windows)
+
+package manifest
+
+import "os"
+
+// Platforms without flock fall back to the in-process lock only.
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
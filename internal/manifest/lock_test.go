@@ -0,0 +1,124 @@
+package manifest_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func record(baseDir, name string, id int64) error {
+	return manifest.Update(baseDir, func(m *manifest.Manifest) error {
+		m.Record(filepath.Join(baseDir, "octocat", name), &models.Repository{ID: id, FullName: "octocat/" + name}, "")
+		return nil
+	})
+}
+
+func TestConcurrentUpdatesKeepEveryEntry(t *testing.T) {
+	baseDir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*25)
+	for writer := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				id := int64(writer*100 + i)
+				errs <- record(baseDir, fmt.Sprintf("repo-%d", id), id)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	m, err := manifest.Load(baseDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(m.Repositories) != 50 {
+		t.Errorf("manifest has %d entries, want all 50 from both writers", len(m.Repositories))
+	}
+}
+
+// TestLockHelper is the second writer of TestLockBlocksOtherProcesses,
+// run in a child process.
+func TestLockHelper(t *testing.T) {
+	baseDir := os.Getenv("GCLONE_LOCK_HELPER_DIR")
+	if baseDir == "" {
+		t.Skip("only run by TestLockBlocksOtherProcesses")
+	}
+	if err := record(baseDir, "child", 2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockBlocksOtherProcesses(t *testing.T) {
+	if !slices.Contains([]string{"linux", "darwin", "freebsd", "openbsd", "dragonfly", "windows"}, runtime.GOOS) {
+		t.Skip("no file locking on this platform")
+	}
+	baseDir := t.TempDir()
+
+	unlock, err := manifest.Lock(baseDir)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+
+	child := exec.Command(os.Args[0], "-test.run=^TestLockHelper$")
+	child.Env = append(os.Environ(), "GCLONE_LOCK_HELPER_DIR="+baseDir)
+	out := make(chan []byte, 1)
+	childErr := make(chan error, 1)
+	go func() {
+		output, err := child.CombinedOutput()
+		out <- output
+		childErr <- err
+	}()
+
+	// The child can't write while the lock is held...
+	select {
+	case err := <-childErr:
+		unlock()
+		t.Fatalf("child finished while the manifest was locked: %v\n%s", err, <-out)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// ...so this read-modify-write can't lose its entry.
+	m, err := manifest.Load(baseDir)
+	if err != nil {
+		unlock()
+		t.Fatalf("Load: %v", err)
+	}
+	m.Record(filepath.Join(baseDir, "octocat", "parent"), &models.Repository{ID: 1, FullName: "octocat/parent"}, "")
+	if err := m.Save(); err != nil {
+		unlock()
+		t.Fatalf("Save: %v", err)
+	}
+	unlock()
+
+	if err := <-childErr; err != nil {
+		t.Fatalf("child: %v\n%s", err, <-out)
+	}
+
+	m, err = manifest.Load(baseDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, name := range []string{"octocat/parent", "octocat/child"} {
+		if m.Repositories[name] == nil {
+			t.Errorf("manifest lost %s: %v", name, m.Repositories)
+		}
+	}
+}
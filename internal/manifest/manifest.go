@@ -0,0 +1,145 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+const (
+	Dir      = ".gclone"
+	FileName = "state.json"
+	version  = 1
+)
+
+// Entry links a local directory to the upstream repository it was cloned
+// from. The repository ID survives renames, so it is what sync and prune
+// match on.
+type Entry struct {
+	ID            int64     `json:"id"`
+	FullName      string    `json:"full_name"`
+	CloneURL      string    `json:"clone_url"`
+	DefaultBranch string    `json:"default_branch"`
+	LastSyncedSHA string    `json:"last_synced_sha,omitempty"`
+	SyncedAt      time.Time `json:"synced_at"`
+}
+
+// Manifest is the state file kept in <BaseDir>/.gclone/state.json, keyed by
+// working copy path relative to BaseDir.
+type Manifest struct {
+	Version      int               `json:"version"`
+	Repositories map[string]*Entry `json:"repositories"`
+
+	baseDir string
+}
+
+func Path(baseDir string) string {
+	return filepath.Join(baseDir, Dir, FileName)
+}
+
+// Load reads the manifest for baseDir, returning an empty one if none has
+// been written yet.
+func Load(baseDir string) (*Manifest, error) {
+	m := &Manifest{
+		Version:      version,
+		Repositories: make(map[string]*Entry),
+		baseDir:      baseDir,
+	}
+
+	data, err := os.ReadFile(Path(baseDir))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", Path(baseDir), err)
+	}
+	if m.Repositories == nil {
+		m.Repositories = make(map[string]*Entry)
+	}
+
+	return m, nil
+}
+
+// Save writes the manifest atomically so an interrupted run never leaves a
+// truncated file behind.
+func (m *Manifest) Save() error {
+	if err := os.MkdirAll(filepath.Join(m.baseDir, Dir), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := Path(m.baseDir) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.Rename(tmp, Path(m.baseDir)); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Key converts a working copy path into the manifest's relative key.
+func (m *Manifest) Key(path string) string {
+	rel, err := filepath.Rel(m.baseDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (m *Manifest) Get(path string) *Entry {
+	return m.Repositories[m.Key(path)]
+}
+
+// Record stores repo as the upstream of the working copy at path.
+func (m *Manifest) Record(path string, repo *models.Repository, sha string) {
+	m.Repositories[m.Key(path)] = &Entry{
+		ID:            repo.ID,
+		FullName:      repo.FullName,
+		CloneURL:      repo.CloneURL,
+		DefaultBranch: repo.DefaultBranch,
+		LastSyncedSHA: sha,
+		SyncedAt:      time.Now().UTC(),
+	}
+}
+
+func (m *Manifest) Remove(path string) {
+	delete(m.Repositories, m.Key(path))
+}
+
+// Move re-keys an entry after its directory was renamed.
+func (m *Manifest) Move(from, to string) {
+	if entry, ok := m.Repositories[m.Key(from)]; ok {
+		delete(m.Repositories, m.Key(from))
+		m.Repositories[m.Key(to)] = entry
+	}
+}
+
+// Paths returns the recorded working copies that still exist on disk,
+// sorted by path.
+func (m *Manifest) Paths() []string {
+	var paths []string
+	for key := range m.Repositories {
+		path := filepath.Join(m.baseDir, filepath.FromSlash(key))
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
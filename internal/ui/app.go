@@ -0,0 +1,632 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+type appState int
+
+const (
+	stateOwner appState = iota
+	stateLoading
+	stateStats
+	stateFilter
+	stateScope
+	stateSelect
+	stateCloning
+)
+
+var (
+	noticeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#EF4444")).
+			Bold(true)
+
+	inputStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#7C3AED")).
+			Padding(0, 1).
+			Width(40)
+
+	spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+)
+
+// Messages the screens send to the app instead of quitting the program.
+type (
+	statsContinueMsg      struct{}
+	filterChosenMsg       struct{ filter models.FilterType }
+	selectionConfirmedMsg struct{ repos []*models.Repository }
+	navigateBackMsg       struct{}
+	spinnerTickMsg        struct{}
+)
+
+type reposLoadedMsg struct {
+	owner string
+	repos []*models.Repository
+	stats *models.RepositoryStats
+	err   error
+}
+
+type scopeChosenMsg struct {
+	filter models.FilterType
+	value  string
+	name   string
+}
+
+type teamsLoadedMsg struct {
+	owner string
+	teams []*github.Team
+	err   error
+}
+
+type scopedReposLoadedMsg struct {
+	owner  string
+	filter models.FilterType
+	name   string
+	repos  []*models.Repository
+	err    error
+}
+
+func emit(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+// AppDeps wires the app to GitHub and the cloner. Cloning and the path
+// helpers are passed in as functions so ui stays a presentation layer that
+// the command assembles, and tests can drive the app without cloning.
+type AppDeps struct {
+	Client *github.Client
+	// Source lists the repositories for an entered name, e.g. what they own
+	// or what they starred.
+	Source    clone.Source
+	LocalInfo func(owner string) func(*models.Repository) *models.LocalRepoInfo
+	PlanPaths func(owner string, repos []*models.Repository) (*layout.Plan, error)
+	// CheckDiskSpace projects the size of cloning repos against the free
+	// space, as the clone itself checks before starting.
+	CheckDiskSpace func(owner string, repos []*models.Repository) (*models.DiskSpaceReport, error)
+	Clone          func(ctx context.Context, repos []*models.Repository, owner string, events clone.Subscriber) error
+}
+
+// AppModel is the single Bubble Tea program driving the whole interactive
+// flow: owner entry, statistics, filter, selection, confirmation and cloning.
+type AppModel struct {
+	deps   AppDeps
+	state  appState
+	width  int
+	height int
+
+	ownerInput string
+	// owner names the run in titles; owners are the individual users or
+	// organizations it covers.
+	owner   string
+	owners  []string
+	notice  string
+	spinner int
+	loading string
+
+	repos  []*models.Repository
+	stats  *models.RepositoryStats
+	filter models.FilterType
+
+	statsModel    *StatsDisplayModel
+	filterModel   *FilterSelectorModel
+	scopeModel    *ScopePickerModel
+	selectorModel *RepositorySelectorModel
+	dashboard     *CloneDashboardModel
+}
+
+// NewAppModel starts at owner entry, or goes straight to fetching when an
+// owner was already given on the command line.
+func NewAppModel(deps AppDeps, owner string) *AppModel {
+	return &AppModel{
+		deps:       deps,
+		state:      stateOwner,
+		ownerInput: owner,
+		width:      80,
+		height:     24,
+	}
+}
+
+func (m *AppModel) Init() tea.Cmd {
+	if m.ownerInput == "" {
+		return nil
+	}
+	return m.submitOwner()
+}
+
+func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.scopeModel != nil {
+			m.scopeModel.Update(msg)
+		}
+		var cmd tea.Cmd
+		if m.selectorModel != nil {
+			_, cmd = m.selectorModel.Update(msg)
+		}
+		if m.dashboard != nil {
+			m.dashboard.Update(msg)
+		}
+		return m, cmd
+
+	case parentLoadedMsg:
+		if m.selectorModel != nil {
+			m.selectorModel.Update(msg)
+		}
+		return m, nil
+
+	case spinnerTickMsg:
+		if m.state != stateLoading && !(m.state == stateCloning && !m.dashboard.finished) {
+			return m, nil
+		}
+		m.spinner = (m.spinner + 1) % len(spinnerFrames)
+		if m.dashboard != nil {
+			m.dashboard.spinner = m.spinner
+		}
+		return m, spinnerTick()
+
+	case reposLoadedMsg:
+		return m.handleReposLoaded(msg)
+
+	case statsContinueMsg:
+		if m.filterModel == nil {
+			m.filterModel = NewFilterSelectorModel(m.stats)
+			if m.isOrganization() {
+				m.filterModel.AddOrganizationScopes(len(TopicItems(m.repos)))
+			}
+		}
+		m.state = stateFilter
+		return m, nil
+
+	case filterChosenMsg:
+		return m.handleFilterChosen(msg)
+
+	case teamsLoadedMsg:
+		return m.handleTeamsLoaded(msg)
+
+	case scopeChosenMsg:
+		return m.handleScopeChosen(msg)
+
+	case scopedReposLoadedMsg:
+		return m.handleScopedReposLoaded(msg)
+
+	case selectionConfirmedMsg:
+		return m.startCloning(msg.repos)
+
+	case navigateBackMsg:
+		m.notice = ""
+		switch m.state {
+		case stateStats:
+			m.state = stateOwner
+		case stateFilter:
+			m.state = stateStats
+		case stateScope:
+			m.state = stateFilter
+		case stateSelect:
+			m.state = stateFilter
+			if m.scopeModel != nil && (m.filter == models.FilterTeam || m.filter == models.FilterTopic) {
+				m.state = stateScope
+			}
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" && m.state != stateCloning {
+			return m, tea.Quit
+		}
+	}
+
+	return m.updateScreen(msg)
+}
+
+// updateScreen forwards everything else to whichever screen is active.
+func (m *AppModel) updateScreen(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch m.state {
+	case stateOwner:
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.handleOwnerInput(key)
+		}
+	case stateStats:
+		_, cmd = m.statsModel.Update(msg)
+	case stateFilter:
+		m.clearNoticeOnKey(msg)
+		_, cmd = m.filterModel.Update(msg)
+	case stateScope:
+		m.clearNoticeOnKey(msg)
+		_, cmd = m.scopeModel.Update(msg)
+	case stateSelect:
+		_, cmd = m.selectorModel.Update(msg)
+	case stateCloning:
+		return m.updateCloning(msg)
+	}
+
+	return m, cmd
+}
+
+func (m *AppModel) clearNoticeOnKey(msg tea.Msg) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.notice = ""
+	}
+}
+
+func (m *AppModel) handleOwnerInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m, tea.Quit
+
+	case tea.KeyEnter:
+		return m, m.submitOwner()
+
+	case tea.KeyBackspace:
+		if len(m.ownerInput) > 0 {
+			runes := []rune(m.ownerInput)
+			m.ownerInput = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeyRunes:
+		m.notice = ""
+		m.ownerInput += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+func (m *AppModel) submitOwner() tea.Cmd {
+	owners := config.ParseOwners(m.ownerInput)
+	if len(owners) == 0 {
+		m.notice = "username cannot be empty"
+		return nil
+	}
+
+	m.notice = ""
+	m.owners = owners
+	m.owner = strings.Join(owners, ", ")
+	m.state = stateLoading
+	m.loading = fmt.Sprintf("Fetching repositories for %s...", m.owner)
+	return tea.Batch(m.loadRepositories(m.owner, owners), spinnerTick())
+}
+
+// loadRepositories lists every owner concurrently and merges the results.
+// Owners that couldn't be listed are reported in the statistics, unless
+// none could.
+func (m *AppModel) loadRepositories(name string, owners []string) tea.Cmd {
+	source := m.deps.Client.CheckedSource(m.deps.Source)
+	return func() tea.Msg {
+		listings := clone.ListOwners(context.Background(), source, owners)
+		repos, err := clone.Merge(listings)
+		if err != nil && clone.AllFailed(listings) {
+			return reposLoadedMsg{owner: name, err: err}
+		}
+		return reposLoadedMsg{owner: name, repos: repos, stats: github.CalculateListingStats(listings)}
+	}
+}
+
+func (m *AppModel) handleReposLoaded(msg reposLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.owner != m.owner || m.state != stateLoading {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		m.state = stateOwner
+		return m, nil
+	}
+
+	if len(msg.repos) == 0 {
+		m.notice = fmt.Sprintf("No repositories found for '%s'.", msg.owner)
+		m.state = stateOwner
+		return m, nil
+	}
+
+	m.repos = msg.repos
+	m.stats = msg.stats
+	m.statsModel = NewStatsDisplayModel(m.stats, msg.owner)
+	m.filterModel = nil
+	m.scopeModel = nil
+	m.selectorModel = nil
+	m.state = stateStats
+
+	return m, nil
+}
+
+func (m *AppModel) handleFilterChosen(msg filterChosenMsg) (tea.Model, tea.Cmd) {
+	switch msg.filter {
+	case models.FilterTeam:
+		m.state = stateLoading
+		m.loading = fmt.Sprintf("Fetching teams of %s...", m.owner)
+		return m, tea.Batch(m.loadTeams(m.owner), spinnerTick())
+	case models.FilterTopic:
+		m.scopeModel = NewScopePickerModel(models.FilterTopic, m.owner, TopicItems(m.repos))
+		m.scopeModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		m.state = stateScope
+		return m, nil
+	}
+
+	filtered := github.FilterRepositories(m.repos, msg.filter)
+	if len(filtered) == 0 {
+		m.notice = "No repositories match the selected filter."
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.selectorModel == nil || m.filter != msg.filter {
+		cmd = m.openSelector(filtered, msg.filter, "")
+	}
+
+	m.filter = msg.filter
+	m.selectorModel.showConfirm = false
+	m.state = stateSelect
+
+	return m, cmd
+}
+
+func (m *AppModel) openSelector(repos []*models.Repository, filter models.FilterType, label string) tea.Cmd {
+	var localInfo func(*models.Repository) *models.LocalRepoInfo
+	if m.deps.LocalInfo != nil {
+		localInfo = m.deps.LocalInfo(m.owner)
+	}
+	m.selectorModel = NewRepositorySelectorModel(repos, filter, localInfo)
+	m.selectorModel.label = label
+	if m.deps.PlanPaths != nil {
+		owner := m.owner
+		m.selectorModel.planPaths = func(repos []*models.Repository) (*layout.Plan, error) {
+			return m.deps.PlanPaths(owner, repos)
+		}
+	}
+	if m.deps.CheckDiskSpace != nil {
+		owner := m.owner
+		m.selectorModel.checkDisk = func(repos []*models.Repository) (*models.DiskSpaceReport, error) {
+			return m.deps.CheckDiskSpace(owner, repos)
+		}
+	}
+	if client := m.deps.Client; client != nil {
+		m.selectorModel.repoDetails = func(fullName string) (*models.Repository, error) {
+			return client.Repository(context.Background(), fullName)
+		}
+	}
+	_, cmd := m.selectorModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	return cmd
+}
+
+// isOrganization reports whether the loaded repositories are the entered
+// organization's own, so its teams and topics can be offered.
+func (m *AppModel) isOrganization() bool {
+	return len(m.owners) == 1 && len(m.repos) > 0 &&
+		strings.EqualFold(m.repos[0].Owner.Login, m.owner) &&
+		m.repos[0].Owner.Type == "Organization"
+}
+
+func (m *AppModel) loadTeams(org string) tea.Cmd {
+	client := m.deps.Client
+	return func() tea.Msg {
+		teams, err := client.Teams(context.Background(), org)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch teams: %w", err)
+		}
+		return teamsLoadedMsg{owner: org, teams: teams, err: err}
+	}
+}
+
+func (m *AppModel) handleTeamsLoaded(msg teamsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.owner != m.owner || m.state != stateLoading {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		m.state = stateFilter
+		return m, nil
+	}
+
+	m.scopeModel = NewScopePickerModel(models.FilterTeam, m.owner, TeamItems(msg.teams))
+	m.scopeModel.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	m.state = stateScope
+	return m, nil
+}
+
+// handleScopeChosen fetches the chosen team's repositories, or searches for
+// the chosen topic, rather than filtering what was already loaded.
+func (m *AppModel) handleScopeChosen(msg scopeChosenMsg) (tea.Model, tea.Cmd) {
+	client := m.deps.Client
+	owner := m.owner
+
+	m.state = stateLoading
+	if msg.filter == models.FilterTeam {
+		m.loading = fmt.Sprintf("Fetching repositories of team %s...", msg.name)
+	} else {
+		m.loading = fmt.Sprintf("Searching %s for topic %s...", owner, msg.name)
+	}
+
+	load := func() tea.Msg {
+		var repos []*models.Repository
+		var err error
+		if msg.filter == models.FilterTeam {
+			repos, err = client.TeamRepositories(context.Background(), owner, msg.value)
+		} else {
+			repos, err = client.TopicRepositories(context.Background(), owner, msg.value)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to fetch repositories: %w", err)
+		}
+		return scopedReposLoadedMsg{owner: owner, filter: msg.filter, name: msg.name, repos: repos, err: err}
+	}
+
+	return m, tea.Batch(load, spinnerTick())
+}
+
+func (m *AppModel) handleScopedReposLoaded(msg scopedReposLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.owner != m.owner || m.state != stateLoading {
+		return m, nil
+	}
+
+	m.state = stateScope
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m, nil
+	}
+	if len(msg.repos) == 0 {
+		m.notice = fmt.Sprintf("No repositories found for %s.", msg.name)
+		return m, nil
+	}
+
+	label := "Team: " + msg.name
+	if msg.filter == models.FilterTopic {
+		label = "Topic: " + msg.name
+	}
+	cmd := m.openSelector(msg.repos, msg.filter, label)
+	m.filter = msg.filter
+	m.state = stateSelect
+
+	return m, cmd
+}
+
+func (m *AppModel) startCloning(repos []*models.Repository) (tea.Model, tea.Cmd) {
+	m.dashboard = NewCloneDashboardModel(m.owner, m.owners, len(repos))
+	m.dashboard.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	m.state = stateCloning
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.dashboard.cancel = cancel
+
+	events := make(chan tea.Msg, 64)
+	subscriber := &channelSubscriber{events: events}
+	cloneRepos := m.deps.Clone
+	owner := m.owner
+
+	run := func() tea.Msg {
+		err := cloneRepos(ctx, repos, owner, subscriber)
+		events <- cloneDoneMsg{err: err}
+		return nil
+	}
+
+	return m, tea.Batch(run, waitForProgress(events), spinnerTick())
+}
+
+func (m *AppModel) updateCloning(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && m.dashboard.finished {
+		switch key.String() {
+		case "enter":
+			m.dashboard = nil
+			m.selectorModel = nil
+			m.filterModel = nil
+			m.scopeModel = nil
+			m.ownerInput = ""
+			m.state = stateOwner
+			return m, nil
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	_, cmd := m.dashboard.Update(msg)
+	return m, cmd
+}
+
+func (m *AppModel) View() string {
+	var view string
+
+	switch m.state {
+	case stateOwner:
+		view = m.renderOwnerEntry()
+	case stateLoading:
+		view = m.renderLoading()
+	case stateStats:
+		view = m.statsModel.View()
+	case stateFilter:
+		view = m.filterModel.View()
+	case stateScope:
+		view = m.scopeModel.View()
+	case stateSelect:
+		view = m.selectorModel.View()
+	case stateCloning:
+		view = m.dashboard.View()
+	}
+
+	if m.notice != "" && m.state != stateOwner {
+		view += "\n" + noticeStyle.Render(m.notice)
+	}
+
+	return view
+}
+
+const welcomeBanner = `                           
+  ____ ___ _____     ____ _     ___  _   _ _____ ____  
+ / ___|_ _|_   _|   / ___| |   / _ \| \ | | ____|  _ \ 
+| |  _ | |  | |    | |   | |  | | | |  \| |  _| | |_) |
+| |_| || |  | |    | |___| |__| |_| | |\  | |___|  _ < 
+ \____|___| |_|     \____|_____\___/|_| \_|_____|_| \_\`
+
+func (m *AppModel) renderOwnerEntry() string {
+	var s strings.Builder
+
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED")).Render(welcomeBanner) + "\n\n")
+	s.WriteString(titleStyle.Render("🚀 Mass Git Cloner") + "\n\n")
+	s.WriteString("Enter GitHub usernames or organizations (comma-separated):\n")
+	s.WriteString(inputStyle.Render(m.ownerInput+cursorStyle.Render("█")) + "\n")
+
+	if m.notice != "" {
+		s.WriteString("\n" + noticeStyle.Render(m.notice) + "\n")
+	}
+
+	s.WriteString(helpStyle.Render("Enter: Fetch repositories    Esc/Ctrl+C: Quit"))
+
+	return s.String()
+}
+
+func (m *AppModel) renderLoading() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render("🚀 Mass Git Cloner") + "\n\n")
+	s.WriteString(fmt.Sprintf("%s %s\n", cursorStyle.Render(spinnerFrames[m.spinner]), m.loading))
+	s.WriteString(helpStyle.Render("Ctrl+C: Quit"))
+
+	return s.String()
+}
+
+// Summary describes the finished run so it can be printed after the
+// alternate screen is torn down.
+func (m *AppModel) Summary() string {
+	if m.dashboard == nil || !m.dashboard.finished {
+		return ""
+	}
+	return m.dashboard.Summary()
+}
+
+// RunApp runs the interactive flow until the user quits.
+func RunApp(deps AppDeps, owner string) error {
+	program := tea.NewProgram(NewAppModel(deps, owner), tea.WithAltScreen())
+
+	finalModel, err := program.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run interactive app: %w", err)
+	}
+
+	if summary := finalModel.(*AppModel).Summary(); summary != "" {
+		DisplaySuccess(summary)
+	}
+
+	return nil
+}
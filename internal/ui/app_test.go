@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// loadOwners submits owners in the owner prompt and delivers the listing.
+func loadOwners(t *testing.T, owners string) *AppModel {
+	t.Helper()
+
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{Name: "hello"})
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = ""
+	client := github.NewClient(cfg)
+
+	m := NewAppModel(AppDeps{Client: client, Source: client}, owners)
+	m.Init()
+	m.Update(m.loadRepositories(m.owner, m.owners)())
+	return m
+}
+
+func TestLoadRepositoriesCarriesOnWithListedOwners(t *testing.T) {
+	m := loadOwners(t, "octocat,ghost")
+
+	if m.state != stateStats {
+		t.Fatalf("state = %v (notice %q), want the statistics of octocat", m.state, m.notice)
+	}
+	if len(m.repos) != 1 || m.repos[0].Name != "hello" {
+		t.Errorf("repos = %v, want octocat/hello", m.repos)
+	}
+	if view := m.View(); !strings.Contains(view, "failed: user or organization 'ghost' not found") {
+		t.Errorf("statistics don't report ghost's failure:\n%s", view)
+	}
+}
+
+func TestLoadRepositoriesReturnsToOwnerWhenAllFail(t *testing.T) {
+	m := loadOwners(t, "ghost,nobody")
+
+	if m.state != stateOwner {
+		t.Fatalf("state = %v, want the owner prompt", m.state)
+	}
+	if !strings.Contains(m.notice, "ghost") || !strings.Contains(m.notice, "nobody") {
+		t.Errorf("notice = %q, want both owners' errors", m.notice)
+	}
+}
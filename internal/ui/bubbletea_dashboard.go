@@ -0,0 +1,372 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+var (
+	phaseStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#9CA3AF"))
+
+	failureStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#EF4444"))
+
+	infoStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#06B6D4"))
+)
+
+type cloneDoneMsg struct{ err error }
+
+// channelSubscriber forwards Manager events into the Bubble Tea event loop.
+type channelSubscriber struct {
+	events chan<- tea.Msg
+}
+
+func (c *channelSubscriber) Handle(event clone.Event) {
+	c.events <- event
+}
+
+func waitForProgress(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return progressEnvelope{msg: <-events, events: events}
+	}
+}
+
+// progressEnvelope carries one event plus the channel to keep listening on.
+type progressEnvelope struct {
+	msg    tea.Msg
+	events <-chan tea.Msg
+}
+
+type activeClone struct {
+	repo     *models.Repository
+	progress models.TransferProgress
+}
+
+type CloneDashboardModel struct {
+	owner         string
+	owners        []string
+	total         int
+	totalBytes    int64
+	finishedBytes int64
+	completed     int
+	failed        int
+	active        []*activeClone
+	failures      []string
+	results       []*models.CloneResult
+	failureScroll int
+	info          string
+	startTime     time.Time
+	endTime       time.Time
+	spinner       int
+	width         int
+	height        int
+	stopping      bool
+	finished      bool
+	err           error
+	cancel        context.CancelFunc
+}
+
+// NewCloneDashboardModel shows a run titled owner; owners are the users
+// or organizations it covers, reported separately in the summary when there
+// are several.
+func NewCloneDashboardModel(owner string, owners []string, total int) *CloneDashboardModel {
+	return &CloneDashboardModel{
+		owner:     owner,
+		owners:    owners,
+		total:     total,
+		startTime: time.Now(),
+		width:     80,
+		height:    24,
+	}
+}
+
+func (m *CloneDashboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *CloneDashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			if m.stopping {
+				return m, tea.Quit
+			}
+			m.stopping = true
+			if m.cancel != nil {
+				m.cancel()
+			}
+
+		case "up", "k":
+			if m.failureScroll > 0 {
+				m.failureScroll--
+			}
+
+		case "down", "j":
+			if m.failureScroll < len(m.failures)-1 {
+				m.failureScroll++
+			}
+		}
+
+	case progressEnvelope:
+		m.apply(msg.msg)
+		if m.finished {
+			return m, nil
+		}
+		return m, waitForProgress(msg.events)
+	}
+
+	return m, nil
+}
+
+func (m *CloneDashboardModel) apply(msg tea.Msg) {
+	switch e := msg.(type) {
+	case clone.RunStarted:
+		m.total = e.Total
+		m.totalBytes = 0
+		m.startTime = time.Now()
+
+	case clone.RepoQueued:
+		m.totalBytes += repoBytes(e.Repository)
+
+	case clone.Message:
+		m.info = strings.TrimSpace(e.Text)
+
+	case clone.RepoStarted:
+		m.active = append(m.active, &activeClone{repo: e.Repository})
+
+	case clone.PhaseChanged:
+		if active := m.findActive(e.Repository); active != nil {
+			active.progress.Phase = e.Phase
+			active.progress.Percent = e.Percent
+			active.progress.BytesPerSec = 0
+		}
+
+	case clone.BytesReceived:
+		if active := m.findActive(e.Repository); active != nil {
+			active.progress.Phase = models.PhaseReceiving
+			active.progress.Percent = e.Percent
+			active.progress.BytesReceived = e.Bytes
+			active.progress.BytesPerSec = e.BytesPerSec
+		}
+
+	case clone.RepoSucceeded:
+		m.finishRepo(e.Repository)
+		m.completed++
+		m.results = append(m.results, &models.CloneResult{Repository: e.Repository, Success: true, Duration: e.Duration, Attempts: e.Attempts})
+
+	case clone.RepoFailed:
+		m.finishRepo(e.Repository)
+		m.failed++
+		m.failures = append(m.failures, fmt.Sprintf("❌ %s: %v", e.Repository.Name, e.Err))
+		m.results = append(m.results, &models.CloneResult{Repository: e.Repository, Error: e.Err, Duration: e.Duration, Attempts: e.Attempts})
+
+	case clone.RunFinished:
+		m.active = nil
+
+	case cloneDoneMsg:
+		m.err = e.err
+		m.finished = true
+		m.endTime = time.Now()
+		if m.cancel != nil {
+			m.cancel()
+		}
+	}
+}
+
+func (m *CloneDashboardModel) finishRepo(repo *models.Repository) {
+	m.removeActive(repo)
+	m.finishedBytes += repoBytes(repo)
+}
+
+func (m *CloneDashboardModel) findActive(repo *models.Repository) *activeClone {
+	for _, clone := range m.active {
+		if clone.repo.ID == repo.ID {
+			return clone
+		}
+	}
+	return nil
+}
+
+func (m *CloneDashboardModel) removeActive(repo *models.Repository) {
+	for i, clone := range m.active {
+		if clone.repo.ID == repo.ID {
+			m.active = append(m.active[:i], m.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// repoBytes converts the API's size (KB) to bytes for ETA estimates.
+func repoBytes(repo *models.Repository) int64 {
+	return int64(repo.Size) * 1024
+}
+
+// eta extrapolates from bytes transferred so far against the combined size
+// of all repositories, counting finished repositories at their API size.
+func (m *CloneDashboardModel) eta() (time.Duration, bool) {
+	done := m.finishedBytes
+	for _, clone := range m.active {
+		done += min(clone.progress.BytesReceived, repoBytes(clone.repo))
+	}
+
+	elapsed := time.Since(m.startTime)
+	if done <= 0 || m.totalBytes <= done || elapsed < time.Second {
+		return 0, false
+	}
+
+	rate := float64(done) / elapsed.Seconds()
+	return time.Duration(float64(m.totalBytes-done) / rate * float64(time.Second)).Truncate(time.Second), true
+}
+
+func (m *CloneDashboardModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render(fmt.Sprintf("🚀 Mass Git Cloner - Cloning %s", m.owner)) + "\n\n")
+
+	done := m.completed + m.failed
+	percentage := 0.0
+	if m.total > 0 {
+		percentage = float64(done) / float64(m.total) * 100
+	}
+
+	status := fmt.Sprintf("[%s] %.1f%% (%d/%d) %s",
+		checkedStyle.Render(progressBar(percentage, 30)), percentage, done, m.total, m.elapsed())
+	if eta, ok := m.eta(); ok && !m.finished {
+		status += fmt.Sprintf("  ETA %s", eta)
+	}
+	s.WriteString(status + "\n")
+	s.WriteString(fmt.Sprintf("%s %d    %s %d\n\n",
+		checkedStyle.Render("Succeeded:"), m.completed, failureStyle.Render("Failed:"), m.failed))
+
+	if m.info != "" {
+		s.WriteString(infoStyle.Render(m.info) + "\n\n")
+	}
+
+	if !m.finished {
+		for _, clone := range m.active {
+			s.WriteString(m.renderActive(clone) + "\n")
+		}
+		if len(m.active) > 0 {
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString(m.renderFailures())
+
+	switch {
+	case m.finished:
+		s.WriteString("\n" + m.Summary() + "\n")
+		s.WriteString(helpStyle.Render("↑/↓: Scroll failures    Enter: Clone from another owner    q: Quit"))
+	case m.stopping:
+		s.WriteString(helpStyle.Render("Stopping... (q again to force quit)"))
+	default:
+		s.WriteString(helpStyle.Render("↑/↓: Scroll failures    q/Ctrl+C: Stop cloning"))
+	}
+
+	return s.String()
+}
+
+func (m *CloneDashboardModel) renderActive(clone *activeClone) string {
+	name := clone.repo.Name
+	if len(name) > 25 {
+		name = name[:22] + "..."
+	}
+
+	line := fmt.Sprintf("%s %-25s %-20s %s %3d%%",
+		cursorStyle.Render(spinnerFrames[m.spinner]),
+		name,
+		phaseStyle.Render(clone.progress.Phase.String()),
+		progressBar(float64(clone.progress.Percent), 15),
+		clone.progress.Percent,
+	)
+
+	if clone.progress.BytesReceived > 0 {
+		line += fmt.Sprintf("  %.1f MB", float64(clone.progress.BytesReceived)/(1024*1024))
+	}
+	if clone.progress.Phase == models.PhaseReceiving && clone.progress.BytesPerSec > 0 {
+		line += fmt.Sprintf(" @ %.2f MB/s", clone.progress.BytesPerSec/(1024*1024))
+	}
+
+	return line
+}
+
+func (m *CloneDashboardModel) renderFailures() string {
+	if len(m.failures) == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(failureStyle.Render(fmt.Sprintf("Failures (%d)", len(m.failures))) + "\n")
+
+	visible := m.height - 16 - len(m.active)
+	if visible < 3 {
+		visible = 3
+	}
+
+	start := m.failureScroll
+	if start > len(m.failures)-1 {
+		start = len(m.failures) - 1
+	}
+	end := start + visible
+	if end > len(m.failures) {
+		end = len(m.failures)
+	}
+
+	for _, line := range m.failures[start:end] {
+		s.WriteString(line + "\n")
+	}
+	if len(m.failures) > visible {
+		s.WriteString(phaseStyle.Render(fmt.Sprintf("Showing %d-%d of %d failures", start+1, end, len(m.failures))) + "\n")
+	}
+
+	return s.String()
+}
+
+func (m *CloneDashboardModel) Summary() string {
+	var s strings.Builder
+
+	if m.err != nil {
+		s.WriteString(noticeStyle.Render(fmt.Sprintf("Cloning failed: %v", m.err)) + "\n")
+	}
+
+	s.WriteString(fmt.Sprintf("🎉 Cloning completed! Total: %d, Successful: %d, Failed: %d, Duration: %s",
+		m.total, m.completed, m.failed, m.elapsed()))
+
+	if len(m.owners) > 1 {
+		for _, line := range OwnerResultLines(m.owners, m.results) {
+			s.WriteString("\n  " + line)
+		}
+	}
+
+	return s.String()
+}
+
+func (m *CloneDashboardModel) elapsed() time.Duration {
+	end := m.endTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(m.startTime).Truncate(time.Second)
+}
+
+func progressBar(percentage float64, width int) string {
+	filled := int(float64(width) * percentage / 100)
+	if filled > width {
+		filled = width
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
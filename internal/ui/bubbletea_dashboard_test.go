@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func TestDashboardSummaryReportsEachOwner(t *testing.T) {
+	repo := func(id int64, owner, name string) *models.Repository {
+		return &models.Repository{ID: id, Name: name, FullName: owner + "/" + name, RequestedOwner: owner}
+	}
+
+	m := NewCloneDashboardModel("octocat, acme", []string{"octocat", "acme"}, 3)
+	m.apply(clone.RunStarted{Total: 3})
+	m.apply(clone.RepoSucceeded{Repository: repo(1, "octocat", "hello")})
+	m.apply(clone.RepoFailed{Repository: repo(2, "octocat", "broken"), Err: errors.New("repository not found")})
+	m.apply(clone.RepoSucceeded{Repository: repo(3, "acme", "tools")})
+	m.apply(clone.RunFinished{})
+	m.apply(cloneDoneMsg{})
+
+	summary := m.Summary()
+	for _, want := range []string{"Total: 3, Successful: 2, Failed: 1", "octocat: succeeded=1 failed=1", "acme: succeeded=1 failed=0"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary = %q, want it to contain %q", summary, want)
+		}
+	}
+
+	single := NewCloneDashboardModel("octocat", []string{"octocat"}, 1)
+	single.apply(clone.RepoSucceeded{Repository: repo(1, "octocat", "hello")})
+	if summary := single.Summary(); strings.Contains(summary, "succeeded=") {
+		t.Errorf("single-owner summary = %q, want no per-owner lines", summary)
+	}
+}
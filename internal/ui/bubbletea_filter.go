@@ -53,6 +53,25 @@ func NewFilterSelectorModel(stats *models.RepositoryStats) *FilterSelectorModel
 	}
 }
 
+// AddOrganizationScopes offers picking one of an organization's teams or
+// one of the topics its repositories are tagged with.
+func (m *FilterSelectorModel) AddOrganizationScopes(topics int) {
+	m.options = append(m.options,
+		FilterOption{
+			Filter:      models.FilterTeam,
+			Name:        "By Team",
+			Description: "Only repositories a team has access to",
+			Count:       -1,
+		},
+		FilterOption{
+			Filter:      models.FilterTopic,
+			Name:        "By Topic",
+			Description: "Only repositories tagged with a topic",
+			Count:       topics,
+		},
+	)
+}
+
 func (m *FilterSelectorModel) Init() tea.Cmd {
 	return nil
 }
@@ -78,7 +97,10 @@ func (m *FilterSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			m.selected = m.options[m.cursor].Filter
 			m.done = true
-			return m, tea.Quit
+			return m, emit(filterChosenMsg{filter: m.selected})
+
+		case "esc":
+			return m, emit(navigateBackMsg{})
 		}
 	}
 
@@ -112,17 +134,19 @@ func (m *FilterSelectorModel) View() string {
 			optionStyle = selectedStyle
 		}
 
-		count := countStyle.Render(fmt.Sprintf("%d", option.Count))
 		name := optionStyle.Render(option.Name)
+		if option.Count >= 0 {
+			name += " " + countStyle.Render(fmt.Sprintf("%d", option.Count))
+		}
 		desc := descStyle.Render(option.Description)
 
-		line := fmt.Sprintf("%s%s %s\n    %s", cursor, name, count, desc)
+		line := fmt.Sprintf("%s%s\n    %s", cursor, name, desc)
 		s.WriteString(line + "\n\n")
 	}
 
 	help := helpStyle.Render(`
 Controls:
-  ↑/k: Move up    ↓/j: Move down    Enter/Space: Select    q: Quit`)
+  ↑/k: Move up    ↓/j: Move down    Enter/Space: Select    Esc: Back    q: Quit`)
 
 	s.WriteString(help)
 
@@ -136,22 +160,3 @@ func (m *FilterSelectorModel) GetSelectedFilter() models.FilterType {
 func (m *FilterSelectorModel) IsDone() bool {
 	return m.done
 }
-
-func ShowFilterSelector(stats *models.RepositoryStats) (models.FilterType, error) {
-	model := NewFilterSelectorModel(stats)
-
-	program := tea.NewProgram(model, tea.WithAltScreen())
-
-	finalModel, err := program.Run()
-	if err != nil {
-		return models.FilterAll, fmt.Errorf("failed to run filter selector: %w", err)
-	}
-
-	filterModel := finalModel.(*FilterSelectorModel)
-
-	if !filterModel.IsDone() {
-		return models.FilterAll, fmt.Errorf("selection cancelled by user")
-	}
-
-	return filterModel.GetSelectedFilter(), nil
-}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// ScopeItem is one team or topic an organization's repositories can be
+// narrowed to. A negative Count is not shown.
+type ScopeItem struct {
+	Value       string
+	Name        string
+	Description string
+	Count       int
+}
+
+// ScopePickerModel lists the teams or topics of an organization after the
+// matching filter was chosen.
+type ScopePickerModel struct {
+	filter models.FilterType
+	owner  string
+	items  []ScopeItem
+	cursor int
+	offset int
+	height int
+}
+
+func NewScopePickerModel(filter models.FilterType, owner string, items []ScopeItem) *ScopePickerModel {
+	return &ScopePickerModel{
+		filter: filter,
+		owner:  owner,
+		items:  items,
+		height: 24,
+	}
+}
+
+// TeamItems lists teams by name.
+func TeamItems(teams []*github.Team) []ScopeItem {
+	items := make([]ScopeItem, 0, len(teams))
+	for _, team := range teams {
+		items = append(items, ScopeItem{
+			Value:       team.Slug,
+			Name:        team.Name,
+			Description: team.Description,
+			Count:       -1,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+	return items
+}
+
+// TopicItems lists the topics repos are tagged with, most used first.
+func TopicItems(repos []*models.Repository) []ScopeItem {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		for _, topic := range repo.Topics {
+			counts[topic]++
+		}
+	}
+
+	items := make([]ScopeItem, 0, len(counts))
+	for topic, count := range counts {
+		items = append(items, ScopeItem{Value: topic, Name: topic, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+func (m *ScopePickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ScopePickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+
+		case "enter", " ":
+			if len(m.items) > 0 {
+				item := m.items[m.cursor]
+				return m, emit(scopeChosenMsg{filter: m.filter, value: item.Value, name: item.Name})
+			}
+
+		case "esc":
+			return m, emit(navigateBackMsg{})
+		}
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// visible is how many items fit below the header and above the help.
+func (m *ScopePickerModel) visible() int {
+	return max(m.height-10, 3)
+}
+
+func (m *ScopePickerModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.visible() {
+		m.offset = m.cursor - m.visible() + 1
+	}
+}
+
+func (m *ScopePickerModel) View() string {
+	var s strings.Builder
+
+	kind := "team"
+	if m.filter == models.FilterTopic {
+		kind = "topic"
+	}
+
+	s.WriteString(titleStyle.Render("🚀 Mass Git Cloner - "+strings.ToUpper(kind[:1])+kind[1:]+" Selection") + "\n\n")
+	s.WriteString(headerStyle.Render(fmt.Sprintf("Select a %s of %s:", kind, m.owner)) + "\n\n")
+
+	if len(m.items) == 0 {
+		s.WriteString(fmt.Sprintf("No %ss found.\n", kind))
+	}
+
+	countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF"))
+	end := min(m.offset+m.visible(), len(m.items))
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+
+		cursor := "  "
+		name := item.Name
+		if m.cursor == i {
+			cursor = cursorStyle.Render("❯ ")
+			name = selectedStyle.Render(name)
+		}
+
+		line := cursor + name
+		if item.Count >= 0 {
+			line += countStyle.Render(fmt.Sprintf(" (%d)", item.Count))
+		}
+		if item.Description != "" {
+			line += countStyle.Render(" - " + item.Description)
+		}
+		s.WriteString(line + "\n")
+	}
+
+	if len(m.items) > m.visible() {
+		s.WriteString(countStyle.Render(fmt.Sprintf("\n%d-%d of %d", m.offset+1, end, len(m.items))) + "\n")
+	}
+
+	s.WriteString(helpStyle.Render(`
+Controls:
+  ↑/k: Move up    ↓/j: Move down    Enter/Space: Select    Esc: Back    q: Quit`))
+
+	return s.String()
+}
@@ -3,9 +3,12 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
 	"github.com/chetanr25/mass-git-cloner/pkg/models"
 )
 
@@ -54,6 +57,14 @@ var (
 			Foreground(lipgloss.Color("#6B7280")).
 			Margin(1, 0)
 
+	previewStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#8B5CF6")).
+			Padding(0, 1)
+
+	previewLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#9CA3AF"))
+
 	confirmStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FAFAFA")).
 			Background(lipgloss.Color("#EF4444")).
@@ -61,24 +72,69 @@ var (
 			Bold(true)
 )
 
+var sortKeys = map[string]models.SortField{
+	"1": models.SortByName,
+	"2": models.SortByStars,
+	"3": models.SortByForks,
+	"4": models.SortBySize,
+	"5": models.SortByUpdated,
+	"6": models.SortByCreated,
+	"7": models.SortByLanguage,
+}
+
 type RepositorySelectorModel struct {
 	repositories []*models.Repository
-	selected     map[int]bool
+	selected     map[int64]bool
 	cursor       int
 	filter       models.FilterType
+	// label replaces the filter's name in the header, e.g. "Team: Platform".
+	label        string
+	sortField    models.SortField
+	sortDesc     bool
+	showPreview  bool
+	planPaths    func([]*models.Repository) (*layout.Plan, error)
+	plan         *layout.Plan
+	planErr      error
+	checkDisk    func([]*models.Repository) (*models.DiskSpaceReport, error)
+	diskSpace    *models.DiskSpaceReport
+	diskSpaceErr error
+	localInfo    func(*models.Repository) *models.LocalRepoInfo
+	localCache   map[int64]*models.LocalRepoInfo
 	showConfirm  bool
 	confirmed    bool
 	done         bool
 	width        int
 	height       int
+
+	// repoDetails fetches a single repository, for the fork parents that
+	// listings leave out. parents caches the results by fork ID; an empty
+	// name means the lookup is still running.
+	repoDetails func(fullName string) (*models.Repository, error)
+	parents     map[int64]string
 }
 
-func NewRepositorySelectorModel(repos []*models.Repository, filter models.FilterType) *RepositorySelectorModel {
+// minPreviewWidth is the narrowest terminal that still fits the list and the
+// detail pane side by side.
+const minPreviewWidth = 100
+
+func NewRepositorySelectorModel(repos []*models.Repository, filter models.FilterType, localInfo func(*models.Repository) *models.LocalRepoInfo) *RepositorySelectorModel {
+	sorted := make([]*models.Repository, len(repos))
+	copy(sorted, repos)
+	// Starred, search, gist and team lists arrive in their own order; sort
+	// them so the header's "updated ↓" is true.
+	github.SortRepositories(sorted, models.SortByUpdated, true)
+
 	return &RepositorySelectorModel{
-		repositories: repos,
-		selected:     make(map[int]bool),
+		repositories: sorted,
+		selected:     make(map[int64]bool),
 		cursor:       0,
 		filter:       filter,
+		sortField:    models.SortByUpdated,
+		sortDesc:     true,
+		showPreview:  true,
+		localInfo:    localInfo,
+		localCache:   make(map[int64]*models.LocalRepoInfo),
+		parents:      make(map[int64]string),
 		showConfirm:  false,
 		confirmed:    false,
 		done:         false,
@@ -96,24 +152,63 @@ func (m *RepositorySelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		return m, m.loadParent()
+
+	case parentLoadedMsg:
+		m.parents[msg.id] = msg.parent
 		return m, nil
 
 	case tea.KeyMsg:
 		if m.showConfirm {
 			return m.handleConfirmation(msg)
 		}
-		return m.handleSelection(msg)
+		model, cmd := m.handleSelection(msg)
+		return model, tea.Batch(cmd, m.loadParent())
 	}
 
 	return m, nil
 }
 
+type parentLoadedMsg struct {
+	id     int64
+	parent string
+}
+
+// loadParent looks up the parent of the fork under the cursor when the
+// preview shows it and the listing didn't include it.
+func (m *RepositorySelectorModel) loadParent() tea.Cmd {
+	if m.repoDetails == nil || !m.previewVisible() {
+		return nil
+	}
+
+	repo := m.repositories[m.cursor]
+	if !repo.IsFork || repo.Parent != nil {
+		return nil
+	}
+	if _, ok := m.parents[repo.ID]; ok {
+		return nil
+	}
+	m.parents[repo.ID] = ""
+
+	fetch, id, fullName := m.repoDetails, repo.ID, repo.FullName
+	return func() tea.Msg {
+		parent := "unknown"
+		if details, err := fetch(fullName); err == nil && details.Parent != nil {
+			parent = details.Parent.FullName
+		}
+		return parentLoadedMsg{id: id, parent: parent}
+	}
+}
+
 func (m *RepositorySelectorModel) handleSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "ctrl+c":
 		m.done = true
 		return m, tea.Quit
 
+	case "esc":
+		return m, emit(navigateBackMsg{})
+
 	case "up", "k":
 		if m.cursor > 0 {
 			m.cursor--
@@ -125,34 +220,92 @@ func (m *RepositorySelectorModel) handleSelection(msg tea.KeyMsg) (tea.Model, te
 		}
 
 	case " ":
-		if m.selected[m.cursor] {
-			delete(m.selected, m.cursor)
+		id := m.repositories[m.cursor].ID
+		if m.selected[id] {
+			delete(m.selected, id)
 		} else {
-			m.selected[m.cursor] = true
+			m.selected[id] = true
 		}
 
 	case "a":
-		for i := range m.repositories {
-			m.selected[i] = true
+		for _, repo := range m.repositories {
+			m.selected[repo.ID] = true
 		}
 
 	case "n":
-		m.selected = make(map[int]bool)
+		m.selected = make(map[int64]bool)
+
+	case "1", "2", "3", "4", "5", "6", "7":
+		field := sortKeys[msg.String()]
+		if field == m.sortField {
+			m.sortDesc = !m.sortDesc
+		} else {
+			m.sortField = field
+			m.sortDesc = defaultSortDescending(field)
+		}
+		m.applySort()
+
+	case "r":
+		m.sortDesc = !m.sortDesc
+		m.applySort()
+
+	case "p":
+		m.showPreview = !m.showPreview
 
 	case "enter":
 		if len(m.selected) > 0 {
 			m.showConfirm = true
+			m.planLayout()
 		}
 	}
 	return m, nil
 }
 
+// applySort re-orders the list and keeps the cursor on the same repository.
+func (m *RepositorySelectorModel) applySort() {
+	if len(m.repositories) == 0 {
+		return
+	}
+
+	current := m.repositories[m.cursor].ID
+	github.SortRepositories(m.repositories, m.sortField, m.sortDesc)
+
+	for i, repo := range m.repositories {
+		if repo.ID == current {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+// defaultSortDescending picks the direction a user most likely wants when
+// switching to a field: biggest/newest first for numbers and dates, A-Z for text.
+func defaultSortDescending(field models.SortField) bool {
+	switch field {
+	case models.SortByName, models.SortByLanguage:
+		return false
+	default:
+		return true
+	}
+}
+
+func (m *RepositorySelectorModel) sortIndicator() string {
+	arrow := "↑"
+	if m.sortDesc {
+		arrow = "↓"
+	}
+	return fmt.Sprintf("sorted by %s %s", m.sortField.String(), arrow)
+}
+
 func (m *RepositorySelectorModel) handleConfirmation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y", "Y":
+		if m.planErr != nil {
+			return m, nil
+		}
 		m.confirmed = true
 		m.done = true
-		return m, tea.Quit
+		return m, emit(selectionConfirmedMsg{repos: m.GetSelectedRepositories()})
 
 	case "n", "N", "esc":
 		m.showConfirm = false
@@ -179,11 +332,17 @@ func (m *RepositorySelectorModel) renderSelection() string {
 	title := titleStyle.Render("🚀 Mass Git Cloner - Repository Selection")
 	s.WriteString(title + "\n\n")
 
-	headerText := fmt.Sprintf("%s - %d selected", m.filter.String(), len(m.selected))
+	label := m.filter.String()
+	if m.label != "" {
+		label = m.label
+	}
+	headerText := fmt.Sprintf("%s - %d selected - %s", label, len(m.selected), m.sortIndicator())
 	header := headerStyle.Render(headerText)
 	s.WriteString(header + "\n\n")
 
-	visibleHeight := m.height - 8
+	split := m.previewVisible()
+
+	visibleHeight := m.height - 10
 	start := 0
 	end := len(m.repositories)
 
@@ -202,35 +361,76 @@ func (m *RepositorySelectorModel) renderSelection() string {
 		}
 	}
 
+	var list strings.Builder
 	for i := start; i < end; i++ {
-		repo := m.repositories[i]
+		list.WriteString(m.renderRow(i, !split) + "\n")
+	}
 
-		cursor := " "
-		if m.cursor == i {
-			cursor = cursorStyle.Render("❯")
-		}
+	if len(m.repositories) > visibleHeight {
+		scrollInfo := fmt.Sprintf("\n📄 Showing %d-%d of %d repositories", start+1, end, len(m.repositories))
+		list.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(scrollInfo) + "\n")
+	}
 
-		checkbox := "☐"
-		checkStyle := uncheckedStyle
-		if m.selected[i] {
-			checkbox = "✓"
-			checkStyle = checkedStyle
-		}
+	if split {
+		listView := lipgloss.NewStyle().Width(m.listWidth()).Render(list.String())
+		detailView := m.renderPreview(m.width - m.listWidth() - 2)
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, listView, "  ", detailView) + "\n")
+	} else {
+		s.WriteString(list.String())
+	}
 
-		repoName := repo.Name
-		if len(repoName) > 25 {
-			repoName = repoName[:22] + "..."
-		}
+	help := helpStyle.Render(`
+Controls:
+  ↑/k: Move up    ↓/j: Move down    Space: Toggle selection
+  a: Select all   n: Select none    Enter: Confirm selection
+  1: Name  2: Stars  3: Forks  4: Size  5: Updated  6: Created  7: Language
+  (press the same key again or r to reverse)    p: Toggle preview
+  Esc: Back to filters    q: Quit`)
 
-		language := repo.Language
-		if language == "" {
-			language = "N/A"
-		}
-		langTag := languageStyle.Render(language)
+	s.WriteString(help)
 
-		stars := starsStyle.Render(fmt.Sprintf("★ %d", repo.StarCount))
-		forks := forksStyle.Render(fmt.Sprintf("⑂ %d", repo.ForkCount))
+	return s.String()
+}
+
+func (m *RepositorySelectorModel) renderRow(i int, withDescription bool) string {
+	repo := m.repositories[i]
+
+	cursor := " "
+	if m.cursor == i {
+		cursor = cursorStyle.Render("❯")
+	}
 
+	checkbox := "☐"
+	checkStyle := uncheckedStyle
+	if m.selected[repo.ID] {
+		checkbox = "✓"
+		checkStyle = checkedStyle
+	}
+
+	repoName := repo.Name
+	if len(repoName) > 25 {
+		repoName = repoName[:22] + "..."
+	}
+
+	language := repo.Language
+	if language == "" {
+		language = "N/A"
+	}
+	langTag := languageStyle.Render(language)
+
+	stars := starsStyle.Render(fmt.Sprintf("★ %d", repo.StarCount))
+	forks := forksStyle.Render(fmt.Sprintf("⑂ %d", repo.ForkCount))
+
+	line := fmt.Sprintf("%s %s %-25s %s %s %s",
+		cursor,
+		checkStyle.Render(checkbox),
+		repoName,
+		langTag,
+		stars,
+		forks,
+	)
+
+	if withDescription {
 		description := repo.Description
 		if len(description) > 40 {
 			description = description[:37] + "..."
@@ -238,38 +438,149 @@ func (m *RepositorySelectorModel) renderSelection() string {
 		if description == "" {
 			description = "No description"
 		}
+		line += " " + description
+	}
 
-		line := fmt.Sprintf("%s %s %-25s %s %s %s %s",
-			cursor,
-			checkStyle.Render(checkbox),
-			repoName,
-			langTag,
-			stars,
-			forks,
-			description,
-		)
-
-		if m.cursor == i {
-			line = selectedStyle.Render(line)
+	if m.cursor == i {
+		line = selectedStyle.Render(line)
+	}
+
+	return line
+}
+
+func (m *RepositorySelectorModel) previewVisible() bool {
+	return m.showPreview && m.width >= minPreviewWidth && len(m.repositories) > 0
+}
+
+func (m *RepositorySelectorModel) listWidth() int {
+	return m.width * 55 / 100
+}
+
+// renderPreview shows everything the list row has to truncate for the
+// repository under the cursor.
+func (m *RepositorySelectorModel) renderPreview(width int) string {
+	repo := m.repositories[m.cursor]
+	inner := width - 4
+	if inner < 20 {
+		inner = 20
+	}
+
+	field := func(label, value string) string {
+		return previewLabelStyle.Render(label+": ") + value + "\n"
+	}
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
 		}
+		return t.Format("2006-01-02")
+	}
 
-		s.WriteString(line + "\n")
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(repo.FullName) + "\n\n")
+
+	description := repo.Description
+	if description == "" {
+		description = "No description"
 	}
+	s.WriteString(lipgloss.NewStyle().Width(inner).Render(description) + "\n\n")
 
-	if len(m.repositories) > visibleHeight {
-		scrollInfo := fmt.Sprintf("\n📄 Showing %d-%d of %d repositories", start+1, end, len(m.repositories))
-		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(scrollInfo) + "\n")
+	topics := "none"
+	if len(repo.Topics) > 0 {
+		topics = strings.Join(repo.Topics, ", ")
+	}
+	s.WriteString(lipgloss.NewStyle().Width(inner).Render(previewLabelStyle.Render("Topics: ")+topics) + "\n")
+	if len(repo.Languages) > 0 {
+		s.WriteString(lipgloss.NewStyle().Width(inner).Render(previewLabelStyle.Render("Languages: ")+languageShares(repo.Languages, repo.LanguageTotal)) + "\n")
 	}
 
-	help := helpStyle.Render(`
-Controls:
-  ↑/k: Move up    ↓/j: Move down    Space: Toggle selection
-  a: Select all   n: Select none    Enter: Confirm selection
-  q: Quit`)
+	license := "none"
+	if repo.License != nil && repo.License.Name != "" {
+		license = repo.License.Name
+	}
+	s.WriteString(field("License", license))
+	s.WriteString(field("Size", fmt.Sprintf("%.1f MB", float64(repo.Size)/1024)))
+	branch := repo.DefaultBranch
+	if len(repo.HeadSHA) >= 7 {
+		branch += " @ " + repo.HeadSHA[:7]
+	}
+	s.WriteString(field("Default branch", branch))
+	s.WriteString(field("Created", date(repo.CreatedAt)))
+	s.WriteString(field("Updated", date(repo.UpdatedAt)))
+	s.WriteString(field("Pushed", date(repo.PushedAt)))
+	if release := repo.LatestRelease; release != nil {
+		s.WriteString(field("Latest release", release.TagName+" ("+date(release.PublishedAt)+")"))
+	}
+	if repo.Gist != nil {
+		visibility := "public"
+		if !repo.Gist.Public {
+			visibility = "secret"
+		}
+		s.WriteString(field("Gist", visibility))
+		s.WriteString(lipgloss.NewStyle().Width(inner).Render(
+			previewLabelStyle.Render("Files: ")+strings.Join(repo.Gist.FileNames(), ", ")) + "\n")
+	}
+	if !repo.StarredAt.IsZero() {
+		s.WriteString(field("Starred", date(repo.StarredAt)))
+	}
 
-	s.WriteString(help)
+	if repo.IsFork {
+		parent := "unknown"
+		if repo.Parent != nil {
+			parent = repo.Parent.FullName
+		} else if name, ok := m.parents[repo.ID]; ok {
+			parent = name
+			if name == "" {
+				parent = "loading…"
+			}
+		}
+		s.WriteString(field("Fork of", parent))
+	}
 
-	return s.String()
+	s.WriteString(field("Local copy", m.localStatus(repo)))
+
+	return previewStyle.Width(width - 2).Render(strings.TrimRight(s.String(), "\n"))
+}
+
+// languageShares lists languages with their share of total, the size of
+// all of a repository's code, like "Go 92%, Shell 8%". Without a total, the
+// languages given are taken to be all of them.
+func languageShares(languages []models.LanguageSize, total int) string {
+	if total == 0 {
+		for _, language := range languages {
+			total += language.Bytes
+		}
+	}
+
+	shares := make([]string, 0, len(languages))
+	for _, language := range languages {
+		if total == 0 {
+			shares = append(shares, language.Name)
+			continue
+		}
+		shares = append(shares, fmt.Sprintf("%s %.0f%%", language.Name, float64(language.Bytes)*100/float64(total)))
+	}
+	return strings.Join(shares, ", ")
+}
+
+func (m *RepositorySelectorModel) localStatus(repo *models.Repository) string {
+	if m.localInfo == nil {
+		return "unknown"
+	}
+
+	info, ok := m.localCache[repo.ID]
+	if !ok {
+		info = m.localInfo(repo)
+		m.localCache[repo.ID] = info
+	}
+
+	switch {
+	case info == nil || !info.Exists:
+		return "not cloned"
+	case info.IsGitRepo:
+		return "already cloned at " + info.Path
+	default:
+		return "path exists but is not a git repository"
+	}
 }
 
 func (m *RepositorySelectorModel) renderConfirmation() string {
@@ -282,8 +593,8 @@ func (m *RepositorySelectorModel) renderConfirmation() string {
 		confirmStyle.Render(fmt.Sprintf("%d", len(m.selected)))))
 
 	count := 0
-	for i, repo := range m.repositories {
-		if m.selected[i] {
+	for _, repo := range m.repositories {
+		if m.selected[repo.ID] {
 			count++
 			langTag := ""
 			if repo.Language != "" {
@@ -303,6 +614,18 @@ func (m *RepositorySelectorModel) renderConfirmation() string {
 
 	s.WriteString("\n")
 
+	if m.planErr != nil {
+		s.WriteString(noticeStyle.Render(m.planErr.Error()) + "\n\n")
+		s.WriteString(helpStyle.Render("n/Esc: Go back and change the selection    q: Quit"))
+		return s.String()
+	}
+
+	if m.plan != nil {
+		s.WriteString(headerStyle.Render("Target layout") + "\n")
+		s.WriteString(m.renderLayoutTree() + "\n")
+		s.WriteString(m.renderProjectedSize() + "\n\n")
+	}
+
 	confirmPrompt := confirmStyle.Render("Do you want to proceed with cloning these repositories? (y/N)")
 	s.WriteString(confirmPrompt + "\n\n")
 
@@ -312,43 +635,79 @@ func (m *RepositorySelectorModel) renderConfirmation() string {
 	return s.String()
 }
 
-func (m *RepositorySelectorModel) GetSelectedRepositories() []*models.Repository {
-	var selected []*models.Repository
-	for i := range m.selected {
-		if i < len(m.repositories) {
-			selected = append(selected, m.repositories[i])
-		}
+// planLayout resolves target paths for the confirmation screen so layout
+// collisions are caught before anything is cloned.
+func (m *RepositorySelectorModel) planLayout() {
+	m.plan, m.planErr = nil, nil
+	if m.planPaths == nil {
+		return
 	}
-	return selected
-}
 
-func (m *RepositorySelectorModel) IsConfirmed() bool {
-	return m.confirmed
-}
+	selected := m.GetSelectedRepositories()
+	m.plan, m.planErr = m.planPaths(selected)
+	if m.planErr != nil {
+		return
+	}
+	m.planErr = m.plan.CollisionError()
 
-func (m *RepositorySelectorModel) IsDone() bool {
-	return m.done
+	m.diskSpace, m.diskSpaceErr = nil, nil
+	if m.checkDisk != nil {
+		m.diskSpace, m.diskSpaceErr = m.checkDisk(selected)
+	}
 }
 
-func ShowRepositorySelector(repos []*models.Repository, filter models.FilterType) ([]*models.Repository, error) {
-	if len(repos) == 0 {
-		return nil, fmt.Errorf("no repositories to select from")
+// renderProjectedSize shows the same disk space check the clone runs first,
+// so repositories already cloned don't count and the margin does.
+func (m *RepositorySelectorModel) renderProjectedSize() string {
+	if m.diskSpace == nil {
+		if m.diskSpaceErr != nil {
+			return "Projected size unknown: " + m.diskSpaceErr.Error()
+		}
+		return ""
 	}
 
-	model := NewRepositorySelectorModel(repos, filter)
+	line := fmt.Sprintf("Projected size: %s (%s with safety margin) — %s free in %s",
+		formatBytes(int64(m.diskSpace.Projected)), formatBytes(int64(m.diskSpace.Required)),
+		formatBytes(int64(m.diskSpace.Available)), m.plan.BaseDir)
+	if !m.diskSpace.Sufficient() {
+		return noticeStyle.Render(line + " — not enough disk space!")
+	}
+	return line
+}
 
-	program := tea.NewProgram(model, tea.WithAltScreen())
+func (m *RepositorySelectorModel) renderLayoutTree() string {
+	paths := make([]string, 0, len(m.plan.Paths))
+	for _, path := range m.plan.Paths {
+		paths = append(paths, path)
+	}
 
-	finalModel, err := program.Run()
-	if err != nil {
-		return nil, fmt.Errorf("failed to run repository selector: %w", err)
+	lines := strings.Split(strings.TrimRight(layout.Tree(m.plan.BaseDir, paths), "\n"), "\n")
+	maxLines := m.height - len(m.selected) - 14
+	if maxLines < 5 {
+		maxLines = 5
+	}
+	if len(lines) > maxLines {
+		hidden := len(lines) - maxLines
+		lines = append(lines[:maxLines], fmt.Sprintf("… %d more", hidden))
 	}
 
-	selectorModel := finalModel.(*RepositorySelectorModel)
+	return strings.Join(lines, "\n") + "\n"
+}
 
-	if !selectorModel.IsConfirmed() {
-		return nil, fmt.Errorf("selection cancelled by user")
+func (m *RepositorySelectorModel) GetSelectedRepositories() []*models.Repository {
+	var selected []*models.Repository
+	for _, repo := range m.repositories {
+		if m.selected[repo.ID] {
+			selected = append(selected, repo)
+		}
 	}
+	return selected
+}
 
-	return selectorModel.GetSelectedRepositories(), nil
+func (m *RepositorySelectorModel) IsConfirmed() bool {
+	return m.confirmed
+}
+
+func (m *RepositorySelectorModel) IsDone() bool {
+	return m.done
 }
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func TestPreviewLoadsParentOfListedFork(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("upstream", &models.Repository{Name: "hello"})
+	server.AddRepos("octocat", &models.Repository{
+		Name:   "hello",
+		IsFork: true,
+		Parent: &models.Repository{FullName: "upstream/hello"},
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.Token = ""
+	client := github.NewClient(cfg)
+
+	repos, err := client.Repositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Repositories: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Parent != nil {
+		t.Fatalf("listing returned %d repositories with parent %v, want one without", len(repos), repos[0].Parent)
+	}
+
+	m := NewRepositorySelectorModel(repos, models.FilterAll, nil)
+	m.repoDetails = func(fullName string) (*models.Repository, error) {
+		return client.Repository(context.Background(), fullName)
+	}
+
+	_, cmd := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	if cmd == nil {
+		t.Fatal("opening the preview on a fork didn't look up its parent")
+	}
+	if view := m.View(); !strings.Contains(view, "loading…") {
+		t.Errorf("preview before the lookup finished:\n%s", view)
+	}
+
+	m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, "upstream/hello") {
+		t.Errorf("preview doesn't name the parent:\n%s", view)
+	}
+
+	if _, cmd := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40}); cmd != nil {
+		t.Error("parent was looked up again instead of cached")
+	}
+	lookups := slices.DeleteFunc(server.Requests(), func(path string) bool { return path != "/repos/octocat/hello" })
+	if len(lookups) != 1 {
+		t.Errorf("looked up the fork %d times, want once", len(lookups))
+	}
+}
+
+func TestLanguageSharesUseTotalSize(t *testing.T) {
+	languages := []models.LanguageSize{{Name: "Go", Bytes: 600}, {Name: "Shell", Bytes: 200}}
+
+	if got := languageShares(languages, 1000); got != "Go 60%, Shell 20%" {
+		t.Errorf("with a total of 1000 = %q", got)
+	}
+	if got := languageShares(languages, 0); got != "Go 75%, Shell 25%" {
+		t.Errorf("without a total = %q", got)
+	}
+}
+
+func TestSelectorSortsAndKeepsSelection(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	repos := []*models.Repository{
+		{ID: 1, Name: "charlie", UpdatedAt: day(2)},
+		{ID: 2, Name: "alpha", UpdatedAt: day(3)},
+		{ID: 3, Name: "bravo", UpdatedAt: day(1)},
+	}
+
+	m := NewRepositorySelectorModel(repos, models.FilterAll, nil)
+	if got := names(m.repositories); !slices.Equal(got, []string{"alpha", "charlie", "bravo"}) {
+		t.Fatalf("initial order = %q, want most recently updated first", got)
+	}
+
+	key := func(k string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)} }
+	m.Update(key("j"))
+	m.Update(key(" "))
+
+	m.Update(key("1"))
+	if got := names(m.repositories); !slices.Equal(got, []string{"alpha", "bravo", "charlie"}) {
+		t.Fatalf("order by name = %q", got)
+	}
+	m.Update(key("r"))
+	if got := names(m.repositories); !slices.Equal(got, []string{"charlie", "bravo", "alpha"}) {
+		t.Fatalf("reversed order = %q", got)
+	}
+
+	selected := m.GetSelectedRepositories()
+	if len(selected) != 1 || selected[0].ID != 1 {
+		t.Errorf("selected = %v, want charlie after sorting", names(selected))
+	}
+	if m.repositories[m.cursor].ID != 1 {
+		t.Errorf("cursor is on %s, want it to follow charlie", m.repositories[m.cursor].Name)
+	}
+}
+
+func names(repos []*models.Repository) []string {
+	out := make([]string, len(repos))
+	for i, repo := range repos {
+		out[i] = repo.Name
+	}
+	return out
+}
@@ -37,7 +37,10 @@ func (m *StatsDisplayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter", " ":
 			m.done = true
-			return m, tea.Quit
+			return m, emit(statsContinueMsg{})
+
+		case "esc":
+			return m, emit(navigateBackMsg{})
 		}
 	}
 
@@ -123,32 +126,38 @@ func (m *StatsDisplayModel) View() string {
 
 	s.WriteString(statsContainer.Render(statsContent.String()) + "\n")
 
-	help := helpStyle.Render("Press Enter or Space to continue, q to quit")
+	if len(m.stats.PerOwner) > 0 {
+		s.WriteString(statsContainer.Render(m.renderPerOwner()) + "\n")
+	}
+
+	help := helpStyle.Render("Press Enter or Space to continue, Esc to change owner, q to quit")
 	s.WriteString(help)
 
 	return s.String()
 }
 
-func (m *StatsDisplayModel) IsDone() bool {
-	return m.done
-}
-
-func ShowRepositoryStats(stats *models.RepositoryStats, username string) error {
-	model := NewStatsDisplayModel(stats, username)
+// renderPerOwner lists each owner's share of a multi-owner listing.
+func (m *StatsDisplayModel) renderPerOwner() string {
+	var content strings.Builder
+	content.WriteString("👥 Per Owner\n\n")
 
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	width := 0
+	for _, owner := range m.stats.PerOwner {
+		width = max(width, len(owner.Owner))
+	}
 
-	_, err := program.Run()
-	if err != nil {
-		return fmt.Errorf("failed to display statistics: %w", err)
+	for _, owner := range m.stats.PerOwner {
+		if owner.Err != nil {
+			content.WriteString(fmt.Sprintf("%-*s  failed: %v\n", width, owner.Owner, owner.Err))
+			continue
+		}
+		content.WriteString(fmt.Sprintf("%-*s  %4d repositories  (%d original, %d forks, %d private)\n",
+			width, owner.Owner, owner.Stats.Total, owner.Stats.NonForks, owner.Stats.Forks, owner.Stats.Private))
 	}
 
-	return nil
+	return strings.TrimSuffix(content.String(), "\n")
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+func (m *StatsDisplayModel) IsDone() bool {
+	return m.done
 }
@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+var statusColumns = []string{"path", "branch", "ahead", "behind", "dirty", "stashes", "upstream"}
+
+type StatusTableModel struct {
+	root     string
+	rows     []*models.RepoStatus
+	cursor   int
+	sortCol  int
+	sortDesc bool
+	width    int
+	height   int
+}
+
+func NewStatusTableModel(root string, rows []*models.RepoStatus) *StatusTableModel {
+	m := &StatusTableModel{
+		root:   root,
+		rows:   rows,
+		width:  80,
+		height: 24,
+	}
+	m.applySort()
+	return m
+}
+
+func (m *StatusTableModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *StatusTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.rows)-1 {
+				m.cursor++
+			}
+
+		case "1", "2", "3", "4", "5", "6", "7":
+			col := int(msg.String()[0] - '1')
+			if col == m.sortCol {
+				m.sortDesc = !m.sortDesc
+			} else {
+				m.sortCol = col
+				m.sortDesc = col >= 2 && col <= 5
+			}
+			m.applySort()
+
+		case "r":
+			m.sortDesc = !m.sortDesc
+			m.applySort()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *StatusTableModel) applySort() {
+	less := func(a, b *models.RepoStatus) bool {
+		switch statusColumns[m.sortCol] {
+		case "branch":
+			return a.Branch < b.Branch
+		case "ahead":
+			return a.Ahead < b.Ahead
+		case "behind":
+			return a.Behind < b.Behind
+		case "dirty":
+			return a.DirtyFiles < b.DirtyFiles
+		case "stashes":
+			return a.Stashes < b.Stashes
+		case "upstream":
+			return a.RemoteState < b.RemoteState
+		default:
+			return a.Path < b.Path
+		}
+	}
+
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		if m.sortDesc {
+			return less(m.rows[j], m.rows[i])
+		}
+		return less(m.rows[i], m.rows[j])
+	})
+}
+
+func (m *StatusTableModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render(fmt.Sprintf("🚀 Mass Git Cloner - Status of %s", m.root)) + "\n\n")
+
+	arrow := "↑"
+	if m.sortDesc {
+		arrow = "↓"
+	}
+	s.WriteString(headerStyle.Render(fmt.Sprintf("%d repositories - sorted by %s %s",
+		len(m.rows), statusColumns[m.sortCol], arrow)) + "\n\n")
+
+	s.WriteString(fmt.Sprintf("  %-35s %-20s %6s %6s %6s %7s  %s\n",
+		"PATH", "BRANCH", "AHEAD", "BEHIND", "DIRTY", "STASHES", "UPSTREAM"))
+
+	visible := m.height - 10
+	start := 0
+	if len(m.rows) > visible && visible > 0 {
+		start = m.cursor - visible/2
+		if start < 0 {
+			start = 0
+		}
+		if start > len(m.rows)-visible {
+			start = len(m.rows) - visible
+		}
+	}
+	end := len(m.rows)
+	if visible > 0 && start+visible < end {
+		end = start + visible
+	}
+
+	for i := start; i < end; i++ {
+		line := m.renderRow(m.rows[i])
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		}
+		s.WriteString(line + "\n")
+	}
+
+	help := helpStyle.Render(`
+Controls:
+  ↑/k: Move up    ↓/j: Move down
+  1: Path  2: Branch  3: Ahead  4: Behind  5: Dirty  6: Stashes  7: Upstream
+  (press the same key again or r to reverse)    q: Quit`)
+	s.WriteString(help)
+
+	return s.String()
+}
+
+func (m *StatusTableModel) renderRow(row *models.RepoStatus) string {
+	path := m.relPath(row.Path)
+	if len(path) > 35 {
+		path = "..." + path[len(path)-32:]
+	}
+
+	if row.Error != "" {
+		return fmt.Sprintf("  %-35s %s", path, failureStyle.Render(row.Error))
+	}
+
+	branch := row.Branch
+	if len(branch) > 20 {
+		branch = branch[:17] + "..."
+	}
+
+	upstream := row.RemoteState
+	switch row.RemoteState {
+	case models.RemoteMissing:
+		upstream = failureStyle.Render(upstream)
+	case models.RemoteExists:
+		upstream = checkedStyle.Render(upstream)
+	}
+
+	dirty := fmt.Sprintf("%6d", row.DirtyFiles)
+	if row.DirtyFiles > 0 {
+		dirty = cursorStyle.Render(dirty)
+	}
+
+	return fmt.Sprintf("  %-35s %-20s %6d %6d %s %7d  %s",
+		path, branch, row.Ahead, row.Behind, dirty, row.Stashes, upstream)
+}
+
+func (m *StatusTableModel) relPath(path string) string {
+	if rel, err := filepath.Rel(m.root, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// ShowStatusTable displays working copy status as an interactive, sortable
+// table.
+func ShowStatusTable(root string, rows []*models.RepoStatus) error {
+	program := tea.NewProgram(NewStatusTableModel(root, rows), tea.WithAltScreen())
+
+	if _, err := program.Run(); err != nil {
+		return fmt.Errorf("failed to display status: %w", err)
+	}
+
+	return nil
+}
+
+// PrintStatusTable writes working copy status as a plain aligned table.
+func PrintStatusTable(out io.Writer, root string, rows []*models.RepoStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tBRANCH\tAHEAD\tBEHIND\tDIRTY\tSTASHES\tUPSTREAM\tERROR")
+
+	for _, row := range rows {
+		path := row.Path
+		if rel, err := filepath.Rel(root, path); err == nil {
+			path = rel
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			path, row.Branch, row.Ahead, row.Behind, row.DirtyFiles, row.Stashes, row.RemoteState, row.Error)
+	}
+
+	w.Flush()
+}
+
+// PrintStatusJSON writes working copy status as a JSON array.
+func PrintStatusJSON(out io.Writer, rows []*models.RepoStatus) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+)
+
+// JSONEvent is one line of the newline-delimited JSON output.
+type JSONEvent struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	Repository string    `json:"repository,omitempty"`
+	Owner      string    `json:"owner,omitempty"`
+	Success    *bool     `json:"success,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Attempts   int       `json:"attempts,omitempty"`
+	Total      *int      `json:"total,omitempty"`
+	Succeeded  *int      `json:"succeeded,omitempty"`
+	Failed     *int      `json:"failed,omitempty"`
+}
+
+// JSONReporter writes run_started, repo_started, repo_finished and
+// run_finished events as newline-delimited JSON. Informational messages go
+// to stderr so the event stream stays machine-readable.
+type JSONReporter struct {
+	encoder *json.Encoder
+	total   int
+	now     func() time.Time
+}
+
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	return &JSONReporter{encoder: json.NewEncoder(out), now: time.Now}
+}
+
+func (j *JSONReporter) Handle(event clone.Event) {
+	switch e := event.(type) {
+	case clone.RunStarted:
+		j.total = e.Total
+		j.emit(JSONEvent{Event: "run_started", Total: intPtr(e.Total)})
+
+	case clone.Message:
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(e.Text))
+
+	case clone.RepoStarted:
+		j.emit(JSONEvent{Event: "repo_started", Repository: e.Repository.DisplayName(), Owner: e.Repository.RequestedOwner})
+
+	case clone.RepoSucceeded:
+		success := true
+		j.emit(JSONEvent{
+			Event:      "repo_finished",
+			Repository: e.Repository.DisplayName(),
+			Owner:      e.Repository.RequestedOwner,
+			Success:    &success,
+			DurationMS: e.Duration.Milliseconds(),
+			Attempts:   e.Attempts,
+		})
+
+	case clone.RepoFailed:
+		success := false
+		j.emit(JSONEvent{
+			Event:      "repo_finished",
+			Repository: e.Repository.DisplayName(),
+			Owner:      e.Repository.RequestedOwner,
+			Success:    &success,
+			Error:      e.Err.Error(),
+			DurationMS: e.Duration.Milliseconds(),
+			Attempts:   e.Attempts,
+		})
+
+	case clone.RunFinished:
+		j.emit(JSONEvent{
+			Event:      "run_finished",
+			Total:      intPtr(j.total),
+			Succeeded:  intPtr(e.Succeeded),
+			Failed:     intPtr(e.Failed),
+			DurationMS: e.Duration.Milliseconds(),
+		})
+	}
+}
+
+func (j *JSONReporter) emit(event JSONEvent) {
+	event.Time = j.now().UTC()
+	j.encoder.Encode(event)
+}
+
+func intPtr(n int) *int {
+	return &n
+}
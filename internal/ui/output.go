@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+type OutputMode string
+
+const (
+	OutputAuto  OutputMode = "auto"
+	OutputTUI   OutputMode = "tui"
+	OutputPlain OutputMode = "plain"
+	OutputJSON  OutputMode = "json"
+)
+
+var colorEnabled = true
+
+// ResolveOutputMode validates the --output flag and turns "auto" into the
+// interactive app on a terminal and plain lines everywhere else.
+func ResolveOutputMode(requested string) (OutputMode, error) {
+	switch OutputMode(requested) {
+	case OutputAuto, "":
+		if IsTerminal() {
+			return OutputTUI, nil
+		}
+		return OutputPlain, nil
+	case OutputTUI:
+		if !IsTerminal() {
+			return "", fmt.Errorf("--output tui needs an interactive terminal")
+		}
+		return OutputTUI, nil
+	case OutputPlain, OutputJSON:
+		return OutputMode(requested), nil
+	default:
+		return "", fmt.Errorf("unknown output mode %q (expected auto, tui, plain or json)", requested)
+	}
+}
+
+func IsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// ConfigureColor disables ANSI styling when NO_COLOR is set or stdout is
+// not a terminal.
+func ConfigureColor() {
+	if os.Getenv("NO_COLOR") != "" || !IsTerminal() {
+		colorEnabled = false
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+func colorize(code, text string) string {
+	if !colorEnabled {
+		return text
+	}
+	return code + text + "\033[0m"
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// OwnerStatsLines describes each owner's share of a multi-owner listing,
+// one line per owner. It is empty when a single owner was listed.
+func OwnerStatsLines(stats *models.RepositoryStats) []string {
+	lines := make([]string, 0, len(stats.PerOwner))
+	for _, owner := range stats.PerOwner {
+		if owner.Err != nil {
+			lines = append(lines, fmt.Sprintf("%s: %v", owner.Owner, owner.Err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d repositories (%d original, %d forks, %d private)",
+			owner.Owner, owner.Stats.Total, owner.Stats.NonForks, owner.Stats.Forks, owner.Stats.Private))
+	}
+	return lines
+}
+
+// OwnerResultLines summarises a run per owner, in the order owners were
+// given.
+func OwnerResultLines(owners []string, results []*models.CloneResult) []string {
+	type tally struct{ succeeded, failed int }
+	tallies := make(map[string]*tally, len(owners))
+	for _, owner := range owners {
+		tallies[owner] = &tally{}
+	}
+
+	for _, result := range results {
+		t, ok := tallies[result.Repository.RequestedOwner]
+		if !ok {
+			continue
+		}
+		if result.Success {
+			t.succeeded++
+		} else {
+			t.failed++
+		}
+	}
+
+	lines := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		t := tallies[owner]
+		lines = append(lines, fmt.Sprintf("%s: succeeded=%d failed=%d", owner, t.succeeded, t.failed))
+	}
+	return lines
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+)
+
+// PlainReporter prints one line per event with no colors or cursor movement,
+// for CI logs and pipes.
+type PlainReporter struct {
+	out   io.Writer
+	total int
+	done  int
+}
+
+func NewPlainReporter(out io.Writer) *PlainReporter {
+	return &PlainReporter{out: out}
+}
+
+// Handle prints run and repository events. Transfer progress is deliberately
+// silent; percentages would flood logs.
+func (p *PlainReporter) Handle(event clone.Event) {
+	switch e := event.(type) {
+	case clone.RunStarted:
+		p.total = e.Total
+		p.done = 0
+		fmt.Fprintf(p.out, "starting: %d repositories\n", p.total)
+
+	case clone.Message:
+		fmt.Fprintln(p.out, strings.TrimSpace(e.Text))
+
+	case clone.RepoStarted:
+		fmt.Fprintf(p.out, "cloning: %s\n", e.Repository.DisplayName())
+
+	case clone.RepoSucceeded:
+		p.done++
+		fmt.Fprintf(p.out, "cloned: %s (%d/%d, %s%s)\n",
+			e.Repository.DisplayName(), p.done, p.total, e.Duration.Truncate(time.Millisecond), attemptsNote(e.Attempts))
+
+	case clone.RepoFailed:
+		p.done++
+		fmt.Fprintf(p.out, "failed: %s (%d/%d, %s%s): %v\n",
+			e.Repository.DisplayName(), p.done, p.total, e.Duration.Truncate(time.Millisecond), attemptsNote(e.Attempts), e.Err)
+
+	case clone.RunFinished:
+		fmt.Fprintf(p.out, "finished: total=%d succeeded=%d failed=%d duration=%s\n",
+			p.total, e.Succeeded, e.Failed, e.Duration.Truncate(time.Second))
+	}
+}
+
+func attemptsNote(attempts int) string {
+	if attempts > 1 {
+		return fmt.Sprintf(", %d attempts", attempts)
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// PrintPlan writes a dry-run plan as an aligned table.
+func PrintPlan(out io.Writer, actions []*models.PlannedAction) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tREPOSITORY\tSIZE\tPATH\tDETAILS")
+
+	counts := make(map[models.CloneAction]int)
+	var totalBytes int64
+
+	for _, action := range actions {
+		counts[action.Action]++
+		totalBytes += action.EstimatedBytes
+
+		details := action.Reason
+		if len(action.Command) > 0 {
+			details = strings.Join(action.Command, " ")
+		} else if action.Description != "" {
+			details = action.Description
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			action.Action, action.Name, formatBytes(action.EstimatedBytes), action.Path, details)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d to clone, %d to update, %d skipped, %d conflicts; estimated download %s\n",
+		counts[models.ActionClone],
+		counts[models.ActionUpdate],
+		counts[models.ActionSkipExists]+counts[models.ActionSkipNotGit],
+		counts[models.ActionConflict],
+		formatBytes(totalBytes))
+}
+
+// PrintPlanJSON writes a dry-run plan as a single JSON document. The disk
+// space report is left out when the check was skipped.
+func PrintPlanJSON(out io.Writer, actions []*models.PlannedAction, report *models.DiskSpaceReport) error {
+	var totalBytes int64
+	for _, action := range actions {
+		totalBytes += action.EstimatedBytes
+	}
+
+	type diskSpace struct {
+		*models.DiskSpaceReport
+		Sufficient bool `json:"sufficient"`
+	}
+	var disk *diskSpace
+	if report != nil {
+		disk = &diskSpace{report, report.Sufficient()}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Actions             []*models.PlannedAction `json:"actions"`
+		TotalEstimatedBytes int64                   `json:"total_estimated_bytes"`
+		DiskSpace           *diskSpace              `json:"disk_space,omitempty"`
+	}{actions, totalBytes, disk})
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n <= 0:
+		return "-"
+	case n >= 1024*1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1024*1024*1024))
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	default:
+		return fmt.Sprintf("%.0f KB", float64(n)/1024)
+	}
+}
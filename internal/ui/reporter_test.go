@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// reporterRun is a run of two repositories, one of which fails after a
+// retry, as the manager publishes it.
+func reporterRun() []clone.Event {
+	hello := &models.Repository{Name: "hello", FullName: "octocat/hello", RequestedOwner: "octocat"}
+	broken := &models.Repository{Name: "broken", FullName: "octocat/broken", RequestedOwner: "octocat"}
+	return []clone.Event{
+		clone.RunStarted{Total: 2},
+		clone.RepoQueued{Repository: hello},
+		clone.RepoQueued{Repository: broken},
+		clone.RepoStarted{Repository: hello},
+		clone.PhaseChanged{Repository: hello, Phase: models.PhaseReceiving, Percent: 50},
+		clone.BytesReceived{Repository: hello, Bytes: 2048, BytesPerSec: 1024, Percent: 50},
+		clone.RepoSucceeded{Repository: hello, Duration: 1500 * time.Millisecond, Attempts: 1},
+		clone.RepoStarted{Repository: broken},
+		clone.RepoFailed{Repository: broken, Err: errors.New("repository not found"), Duration: 250 * time.Millisecond, Attempts: 2},
+		clone.RunFinished{Succeeded: 1, Failed: 1, Duration: 2 * time.Second},
+	}
+}
+
+func TestPlainReporterGolden(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewPlainReporter(&out)
+	reporter.Handle(clone.Message{Text: "  Skipping octocat/other: already exists  "})
+	for _, event := range reporterRun() {
+		reporter.Handle(event)
+	}
+
+	checkGolden(t, "plain.golden", out.Bytes())
+}
+
+func TestJSONReporterGolden(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewJSONReporter(&out)
+	reporter.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	for _, event := range reporterRun() {
+		reporter.Handle(event)
+	}
+
+	checkGolden(t, "json.golden", out.Bytes())
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package workspace
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/internal/cloner"
+)
+
+// Discover walks root and returns every git working copy below it. It does
+// not descend into a working copy once found, and skips hidden directories
+// such as .gclone and wikis cloned next to their repository as <repo>.wiki.
+func Discover(root string) ([]string, error) {
+	var repos []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		if isWiki(path) {
+			return filepath.SkipDir
+		}
+
+		info, _ := cloner.GetRepositoryInfo(path)
+		if info.IsGitRepo {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return repos, err
+}
+
+// isWiki reports whether path is a <repo>.wiki directory next to <repo>.
+func isWiki(path string) bool {
+	repo, ok := strings.CutSuffix(path, ".wiki")
+	if !ok {
+		return false
+	}
+	info, err := os.Stat(repo)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,157 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type ExecOptions struct {
+	Command     []string
+	Root        string
+	Concurrency int
+	Timeout     time.Duration
+	// Aggregate prints each repository's output as one block when it
+	// finishes instead of interleaving prefixed lines as they arrive.
+	Aggregate bool
+	Out       io.Writer
+}
+
+type ExecResult struct {
+	Path     string
+	ExitCode int
+	Err      error
+	Duration time.Duration
+}
+
+func (r *ExecResult) Passed() bool {
+	return r.Err == nil
+}
+
+// Exec runs opts.Command in every path with bounded parallelism and a
+// per-repository timeout, the same way GitCloner bounds git commands.
+func Exec(ctx context.Context, paths []string, opts ExecOptions) []*ExecResult {
+	if len(opts.Command) == 0 {
+		return nil
+	}
+
+	var outMu sync.Mutex
+	results := make([]*ExecResult, len(paths))
+
+	forEachPath(opts.Concurrency, paths, func(i int, path string) {
+		label := path
+		if rel, err := filepath.Rel(opts.Root, path); err == nil {
+			label = rel
+		}
+
+		if ctx.Err() != nil {
+			results[i] = &ExecResult{Path: path, ExitCode: -1, Err: ctx.Err()}
+			return
+		}
+
+		results[i] = runIn(ctx, path, label, opts, &outMu)
+	})
+
+	return results
+}
+
+func runIn(ctx context.Context, path, label string, opts ExecOptions, outMu *sync.Mutex) *ExecResult {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, opts.Command[0], opts.Command[1:]...)
+	cmd.Dir = path
+	// Don't wait forever on grandchildren that keep the output pipes open
+	// after the command itself was killed.
+	cmd.WaitDelay = time.Second
+
+	var block bytes.Buffer
+	var out io.Writer
+	if opts.Aggregate {
+		out = &block
+	} else {
+		out = &prefixWriter{prefix: "[" + label + "] ", out: opts.Out, mu: outMu}
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &ExecResult{Path: path, Duration: time.Since(start)}
+
+	if pw, ok := out.(*prefixWriter); ok {
+		pw.Flush()
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", opts.Timeout)
+	}
+
+	if err != nil {
+		result.Err = err
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	if opts.Aggregate {
+		outMu.Lock()
+		fmt.Fprintf(opts.Out, "==> %s\n%s", label, block.String())
+		if block.Len() > 0 && !bytes.HasSuffix(block.Bytes(), []byte("\n")) {
+			fmt.Fprintln(opts.Out)
+		}
+		outMu.Unlock()
+	}
+
+	return result
+}
+
+// prefixWriter writes whole lines with a repository prefix so output from
+// parallel commands stays readable.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	mu     *sync.Mutex
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	io.WriteString(w.out, w.prefix)
+	w.out.Write(line)
+}
@@ -0,0 +1,221 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+)
+
+const lockfileVersion = 1
+
+// Lockfile pins every working copy of a workspace to a commit so the same
+// checkout can be reproduced elsewhere.
+type Lockfile struct {
+	Version      int          `json:"version"`
+	Repositories []*LockEntry `json:"repositories"`
+}
+
+// LockEntry is one pinned working copy. Path is relative to the workspace
+// root and always uses forward slashes.
+type LockEntry struct {
+	Path   string `json:"path"`
+	URL    string `json:"url"`
+	Commit string `json:"commit"`
+}
+
+// Lock records the origin URL and HEAD commit of every working copy below
+// root. Working copies without an origin or a commit can't be reproduced and
+// are returned as errors alongside the lockfile.
+func Lock(ctx context.Context, cfg *config.Config, root string) (*Lockfile, []error, error) {
+	paths, err := Discover(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	entries := make([]*LockEntry, len(paths))
+	errs := make([]error, len(paths))
+	forEachPath(cfg.Concurrency, paths, func(i int, path string) {
+		entries[i], errs[i] = lockEntry(ctx, root, path)
+	})
+
+	lf := &Lockfile{Version: lockfileVersion}
+	var skipped []error
+	for i := range paths {
+		if errs[i] != nil {
+			skipped = append(skipped, errs[i])
+			continue
+		}
+		lf.Repositories = append(lf.Repositories, entries[i])
+	}
+
+	return lf, skipped, nil
+}
+
+func lockEntry(ctx context.Context, root, path string) (*LockEntry, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := git(ctx, path, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("%s: no origin remote", rel)
+	}
+
+	commit, err := git(ctx, path, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("%s: no commit checked out", rel)
+	}
+
+	return &LockEntry{Path: filepath.ToSlash(rel), URL: url, Commit: commit}, nil
+}
+
+func WriteLockfile(path string, lf *Lockfile) error {
+	data, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var lf Lockfile
+	if err := json.Unmarshal(data, &lf); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if lf.Version != lockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d", lf.Version)
+	}
+
+	for _, entry := range lf.Repositories {
+		rel := filepath.Clean(filepath.FromSlash(entry.Path))
+		if filepath.IsAbs(rel) || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("lockfile entry %q points outside the workspace", entry.Path)
+		}
+		// Both end up on git's command line; anything else could be read
+		// as an option.
+		if !isCommitHash(entry.Commit) {
+			return nil, fmt.Errorf("lockfile entry %q has an invalid commit %q", entry.Path, entry.Commit)
+		}
+		if entry.URL == "" || strings.HasPrefix(entry.URL, "-") {
+			return nil, fmt.Errorf("lockfile entry %q has an invalid url %q", entry.Path, entry.URL)
+		}
+	}
+
+	return &lf, nil
+}
+
+// isCommitHash reports whether s is a full SHA-1 or SHA-256 object name.
+func isCommitHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// RestoreResult is the outcome of restoring one lockfile entry.
+type RestoreResult struct {
+	Entry  *LockEntry
+	Cloned bool
+	// Unreachable is set when the pinned commit could not be fetched from
+	// the remote, e.g. after a force push.
+	Unreachable bool
+	Err         error
+}
+
+// Restore clones or fetches every entry of lf below root and checks out its
+// pinned commit.
+func Restore(ctx context.Context, cfg *config.Config, root string, lf *Lockfile) []*RestoreResult {
+	paths := make([]string, len(lf.Repositories))
+	for i, entry := range lf.Repositories {
+		paths[i] = filepath.Join(root, filepath.FromSlash(entry.Path))
+	}
+
+	results := make([]*RestoreResult, len(paths))
+	forEachPath(cfg.Concurrency, paths, func(i int, path string) {
+		ctx, cancel := context.WithTimeout(ctx, cfg.CloneTimeout)
+		defer cancel()
+
+		results[i] = restoreEntry(ctx, lf.Repositories[i], path)
+	})
+
+	return results
+}
+
+func restoreEntry(ctx context.Context, entry *LockEntry, path string) *RestoreResult {
+	result := &RestoreResult{Entry: entry}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			result.Err = err
+			return result
+		}
+		cmd := exec.CommandContext(ctx, "git", "clone", "--no-checkout", "--", entry.URL, path)
+		cmd.Env = gitEnv()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			result.Err = fmt.Errorf("git clone failed: %s", lastLine(out))
+			return result
+		}
+		result.Cloned = true
+	} else if _, err := git(ctx, path, "fetch", "--quiet", "--", "origin"); err != nil {
+		result.Err = err
+		return result
+	}
+
+	if !hasCommit(ctx, path, entry.Commit) {
+		// Commits no branch points at any more aren't fetched by default;
+		// servers that allow it will still hand them out by SHA.
+		git(ctx, path, "fetch", "--quiet", "--", "origin", entry.Commit)
+		if !hasCommit(ctx, path, entry.Commit) {
+			result.Unreachable = true
+			result.Err = fmt.Errorf("pinned commit %s is no longer reachable upstream", ShortSHA(entry.Commit))
+			return result
+		}
+	}
+
+	// The trailing -- keeps the commit from being taken as a path.
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "checkout", "--quiet", "--detach", entry.Commit, "--")
+	cmd.Env = gitEnv()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		result.Err = fmt.Errorf("git checkout failed: %s", lastLine(out))
+	}
+
+	return result
+}
+
+func hasCommit(ctx context.Context, path, commit string) bool {
+	_, err := git(ctx, path, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// ShortSHA abbreviates a commit hash for display.
+func ShortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return lines[len(lines)-1]
+}
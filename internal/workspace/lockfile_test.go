@@ -0,0 +1,109 @@
+package workspace_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/internal/workspace"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+func writeLockfile(t *testing.T, lf *workspace.Lockfile) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "gclone.lock")
+	if err := workspace.WriteLockfile(path, lf); err != nil {
+		t.Fatalf("WriteLockfile: %v", err)
+	}
+	return path
+}
+
+func TestRestoreChecksOutPinnedCommits(t *testing.T) {
+	_, cfg := clonePrune(t, &models.Repository{Name: "hello"}, &models.Repository{Name: "other"})
+
+	lf, skipped, err := workspace.Lock(context.Background(), cfg, cfg.BaseDir)
+	if err != nil || len(skipped) > 0 {
+		t.Fatalf("Lock: %v %v", err, skipped)
+	}
+	lf, err = workspace.ReadLockfile(writeLockfile(t, lf))
+	if err != nil {
+		t.Fatalf("ReadLockfile: %v", err)
+	}
+
+	root := t.TempDir()
+	results := workspace.Restore(context.Background(), cfg, root, lf)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil || !result.Cloned {
+			t.Errorf("%s: cloned %v, err %v", result.Entry.Path, result.Cloned, result.Err)
+			continue
+		}
+		out, err := exec.Command("git", "-C", filepath.Join(root, result.Entry.Path), "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatalf("rev-parse: %v", err)
+		}
+		if head := strings.TrimSpace(string(out)); head != result.Entry.Commit {
+			t.Errorf("%s: HEAD = %s, want %s", result.Entry.Path, head, result.Entry.Commit)
+		}
+	}
+
+	// Restoring again fetches into the existing working copies.
+	for _, result := range workspace.Restore(context.Background(), cfg, root, lf) {
+		if result.Err != nil || result.Cloned {
+			t.Errorf("%s again: cloned %v, err %v", result.Entry.Path, result.Cloned, result.Err)
+		}
+	}
+}
+
+func TestReadLockfileRejectsOptionsOnTheCommandLine(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	dir := t.TempDir()
+	pwned := filepath.Join(dir, "PWNED")
+
+	tests := []*workspace.LockEntry{
+		{Path: "octocat/hello", URL: "https://github.com/octocat/hello.git", Commit: "--upload-pack=touch " + pwned + ";git-upload-pack"},
+		{Path: "octocat/hello", URL: "https://github.com/octocat/hello.git", Commit: "main"},
+		{Path: "octocat/hello", URL: "https://github.com/octocat/hello.git", Commit: commit[:12]},
+		{Path: "octocat/hello", URL: "--upload-pack=touch " + pwned, Commit: commit},
+		{Path: "octocat/hello", URL: "", Commit: commit},
+	}
+
+	for _, entry := range tests {
+		path := writeLockfile(t, &workspace.Lockfile{Version: 1, Repositories: []*workspace.LockEntry{entry}})
+		if _, err := workspace.ReadLockfile(path); err == nil {
+			t.Errorf("lockfile with url %q and commit %q was accepted", entry.URL, entry.Commit)
+		}
+	}
+	if _, err := os.Stat(pwned); !os.IsNotExist(err) {
+		t.Error("a lockfile entry ran a command")
+	}
+}
+
+func TestRestoreReportsUnreachableCommits(t *testing.T) {
+	_, cfg := clonePrune(t, &models.Repository{Name: "hello"})
+
+	lf, _, err := workspace.Lock(context.Background(), cfg, cfg.BaseDir)
+	if err != nil || len(lf.Repositories) != 1 {
+		t.Fatalf("Lock: %v %+v", err, lf)
+	}
+	// A well-formed commit the remote has never seen, as after a force push.
+	lf.Repositories[0].Commit = strings.Repeat("0", 40)
+	lf, err = workspace.ReadLockfile(writeLockfile(t, lf))
+	if err != nil {
+		t.Fatalf("ReadLockfile: %v", err)
+	}
+
+	results := workspace.Restore(context.Background(), cfg, t.TempDir(), lf)
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if !results[0].Unreachable || results[0].Err == nil {
+		t.Errorf("result = unreachable %v, err %v; want an unreachable commit", results[0].Unreachable, results[0].Err)
+	}
+}
@@ -0,0 +1,250 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// PruneKind is what reconciling does with one local working copy.
+type PruneKind string
+
+const (
+	// PruneAdopt records a working copy matched by its remote URL in the
+	// manifest, so later runs can match it by ID.
+	PruneAdopt PruneKind = "adopt"
+	// PruneRename moves a working copy whose repository was renamed upstream.
+	PruneRename PruneKind = "rename"
+	// PruneArchive moves a working copy whose repository no longer exists.
+	PruneArchive PruneKind = "archive"
+	// PruneUnknown reports a working copy whose repository wasn't listed
+	// but can't be confirmed deleted. Applying it does nothing.
+	PruneUnknown PruneKind = "unknown"
+)
+
+// PruneAction is one change reconciling would make.
+type PruneAction struct {
+	Kind       PruneKind
+	Path       string
+	NewPath    string
+	Repository *models.Repository
+	Reason     string
+}
+
+// Reconcile compares the owner's working copies below cfg.BaseDir with the
+// repositories GitHub reports for them. Working copies are matched by the
+// repository ID stored in the manifest, falling back to the origin remote for
+// clones made before the manifest existed. Repositories missing from repos
+// are looked up with client before anything is archived.
+func Reconcile(ctx context.Context, cfg *config.Config, client *github.Client, man *manifest.Manifest, owner string, repos []*models.Repository) ([]*PruneAction, error) {
+	l, err := layout.Parse(cfg.Layout)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := Discover(cfg.BaseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", cfg.BaseDir, err)
+	}
+
+	byID := make(map[int64]*models.Repository, len(repos))
+	byName := make(map[string]*models.Repository, len(repos))
+	for _, repo := range repos {
+		byID[repo.ID] = repo
+		byName[strings.ToLower(repo.FullName)] = repo
+	}
+
+	r := &reconciler{cfg: cfg, client: client, man: man, layout: l, owner: owner, byID: byID, byName: byName}
+
+	var actions []*PruneAction
+	for _, path := range paths {
+		action, err := r.reconcilePath(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			actions = append(actions, action)
+		}
+	}
+
+	return actions, nil
+}
+
+type reconciler struct {
+	cfg    *config.Config
+	client *github.Client
+	man    *manifest.Manifest
+	layout *layout.Layout
+	owner  string
+	byID   map[int64]*models.Repository
+	byName map[string]*models.Repository
+}
+
+func (r *reconciler) reconcilePath(ctx context.Context, path string) (*PruneAction, error) {
+	entry := r.man.Get(path)
+
+	if entry == nil {
+		remote, err := git(ctx, path, "remote", "get-url", "origin")
+		if err != nil {
+			return nil, nil
+		}
+		fullName, ok := GitHubFullName(remote)
+		if !ok || !ownedBy(fullName, r.owner) {
+			return nil, nil
+		}
+		// Wikis cloned next to their repository aren't listed by the API.
+		if strings.HasSuffix(strings.ToLower(fullName), ".wiki") {
+			return nil, nil
+		}
+
+		repo, found := r.byName[strings.ToLower(fullName)]
+		if !found {
+			return r.missing(ctx, path, fullName, func(ctx context.Context) (*models.Repository, error) {
+				return r.client.Repository(ctx, fullName)
+			})
+		}
+		return &PruneAction{
+			Kind:       PruneAdopt,
+			Path:       path,
+			Repository: repo,
+			Reason:     "matched by remote URL",
+		}, nil
+	}
+
+	// Gists aren't part of the repository listing, and their IDs aren't
+	// repository IDs.
+	if !ownedBy(entry.FullName, r.owner) || strings.Contains(entry.FullName, "/gists/") {
+		return nil, nil
+	}
+
+	repo, found := r.byID[entry.ID]
+	if !found {
+		return r.missing(ctx, path, entry.FullName, func(ctx context.Context) (*models.Repository, error) {
+			return r.client.RepositoryByID(ctx, entry.ID)
+		})
+	}
+
+	if strings.EqualFold(repo.FullName, entry.FullName) {
+		return nil, nil
+	}
+	return r.moved(path, entry.FullName, repo)
+}
+
+// missing decides what to do with a working copy whose repository wasn't
+// listed. The listing leaves out transferred repositories and, without a
+// token, private ones, and GitHub answers 404 for private repositories the
+// caller can't see. So only a lookup with a token that finds nothing
+// archives; anything else is reported as unknown.
+func (r *reconciler) missing(ctx context.Context, path, fullName string, lookup func(context.Context) (*models.Repository, error)) (*PruneAction, error) {
+	if r.client == nil {
+		return unknown(path, fmt.Sprintf("%s wasn't listed and can't be looked up", fullName)), nil
+	}
+
+	repo, err := lookup(ctx)
+	switch {
+	case err == nil && strings.EqualFold(repo.FullName, fullName):
+		return unknown(path, fmt.Sprintf("%s still exists upstream but wasn't listed", fullName)), nil
+	case err == nil:
+		return r.moved(path, fullName, repo)
+	case errors.Is(err, github.ErrNotFound) && r.client.Authenticated():
+		return &PruneAction{
+			Kind:   PruneArchive,
+			Path:   path,
+			Reason: fmt.Sprintf("%s no longer exists upstream", fullName),
+		}, nil
+	case errors.Is(err, github.ErrNotFound):
+		return unknown(path, fmt.Sprintf("%s wasn't found, but it may be private; set GITHUB_TOKEN to check", fullName)), nil
+	default:
+		return unknown(path, fmt.Sprintf("%s wasn't listed and looking it up failed: %v", fullName, err)), nil
+	}
+}
+
+// moved proposes moving the working copy of a repository that was renamed
+// or transferred to another owner.
+func (r *reconciler) moved(path, oldName string, repo *models.Repository) (*PruneAction, error) {
+	newPath, err := r.layout.Path(r.cfg.BaseDir, r.owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("renamed upstream from %s to %s", oldName, repo.FullName)
+	if !ownedBy(repo.FullName, r.owner) {
+		reason = fmt.Sprintf("transferred upstream from %s to %s", oldName, repo.FullName)
+	}
+
+	return &PruneAction{
+		Kind:       PruneRename,
+		Path:       path,
+		NewPath:    newPath,
+		Repository: repo,
+		Reason:     reason,
+	}, nil
+}
+
+func unknown(path, reason string) *PruneAction {
+	return &PruneAction{Kind: PruneUnknown, Path: path, Reason: reason}
+}
+
+func ownedBy(fullName, owner string) bool {
+	return strings.HasPrefix(strings.ToLower(fullName), strings.ToLower(owner)+"/")
+}
+
+// ApplyPrune carries out action and updates man to match. The caller saves
+// the manifest.
+func ApplyPrune(ctx context.Context, cfg *config.Config, man *manifest.Manifest, action *PruneAction) error {
+	switch action.Kind {
+	case PruneAdopt:
+		head, _ := git(ctx, action.Path, "rev-parse", "HEAD")
+		man.Record(action.Path, action.Repository, head)
+
+	case PruneRename:
+		if action.NewPath != action.Path {
+			if _, err := os.Stat(action.NewPath); err == nil {
+				return fmt.Errorf("cannot rename %s: %s already exists", action.Path, action.NewPath)
+			}
+			if err := os.MkdirAll(filepath.Dir(action.NewPath), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(action.NewPath), err)
+			}
+			if err := os.Rename(action.Path, action.NewPath); err != nil {
+				return fmt.Errorf("failed to rename %s: %w", action.Path, err)
+			}
+		}
+		if _, err := git(ctx, action.NewPath, "remote", "set-url", "origin", action.Repository.CloneURL); err != nil {
+			return fmt.Errorf("failed to update remote for %s: %w", action.NewPath, err)
+		}
+		man.Remove(action.Path)
+		head, _ := git(ctx, action.NewPath, "rev-parse", "HEAD")
+		man.Record(action.NewPath, action.Repository, head)
+
+	case PruneArchive:
+		target := ArchivePath(cfg.BaseDir, man.Key(action.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create archive directory: %w", err)
+		}
+		if err := os.Rename(action.Path, target); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", action.Path, err)
+		}
+		man.Remove(action.Path)
+		action.NewPath = target
+	}
+
+	return nil
+}
+
+// ArchivePath is where a working copy at the manifest key rel is moved when
+// its repository is deleted upstream. Archives are dated so pruning the same
+// path twice never overwrites an earlier archive.
+func ArchivePath(baseDir, rel string) string {
+	stamp := time.Now().Format("20060102-150405")
+	return filepath.Join(baseDir, manifest.Dir, "archive", filepath.FromSlash(rel)+"-"+stamp)
+}
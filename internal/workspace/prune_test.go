@@ -0,0 +1,155 @@
+package workspace_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chetanr25/mass-git-cloner/internal/cloner"
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/internal/manifest"
+	"github.com/chetanr25/mass-git-cloner/internal/workspace"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// clonePrune serves repos as octocat's from the fake API, backed by local
+// bare repositories, and clones them into a temp directory.
+func clonePrune(t *testing.T, repos ...*models.Repository) (*githubtest.Server, *config.Config) {
+	t.Helper()
+
+	remotes := t.TempDir()
+	server := githubtest.NewServer(t)
+	for _, repo := range repos {
+		repo.CloneURL = githubtest.BareRepo(t, remotes, repo.Name)
+		server.AddRepos("octocat", repo)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = server.URL
+	cfg.BaseDir = t.TempDir()
+	cfg.Token = ""
+
+	if err := cloner.NewManager(cfg).CloneRepositories(repos, "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+	return server, cfg
+}
+
+func reconcile(t *testing.T, cfg *config.Config) []*workspace.PruneAction {
+	t.Helper()
+
+	client := github.NewClient(cfg)
+	repos, err := client.GetRepositories("octocat")
+	if err != nil {
+		t.Fatalf("GetRepositories: %v", err)
+	}
+	man, err := manifest.Load(cfg.BaseDir)
+	if err != nil {
+		t.Fatalf("manifest.Load: %v", err)
+	}
+
+	actions, err := workspace.Reconcile(context.Background(), cfg, client, man, "octocat", repos)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	return actions
+}
+
+func TestReconcileFollowsTransfers(t *testing.T) {
+	server, cfg := clonePrune(t, &models.Repository{Name: "hello"}, &models.Repository{Name: "other"})
+	server.Transfer("octocat", "hello", "hubot")
+
+	actions := reconcile(t, cfg)
+	if len(actions) != 1 {
+		t.Fatalf("got %d actions, want 1: %+v", len(actions), actions)
+	}
+
+	action := actions[0]
+	if action.Kind != workspace.PruneRename || action.Repository.FullName != "hubot/hello" {
+		t.Fatalf("action = %s to %v, want a rename to hubot/hello", action.Kind, action.Repository)
+	}
+	if !strings.Contains(action.Reason, "transferred") {
+		t.Errorf("Reason = %q, want it to mention the transfer", action.Reason)
+	}
+}
+
+func TestReconcileKeepsPrivateRepositoriesListedWithoutToken(t *testing.T) {
+	server, cfg := clonePrune(t, &models.Repository{Name: "secret", IsPrivate: true}, &models.Repository{Name: "gone"})
+	server.RemoveRepo("octocat", "gone")
+	server.AddToken("secret-token", "octocat")
+
+	man, err := manifest.Load(cfg.BaseDir)
+	if err != nil {
+		t.Fatalf("manifest.Load: %v", err)
+	}
+
+	actions := reconcile(t, cfg)
+	if len(actions) != 2 {
+		t.Fatalf("got %d actions without a token, want 2: %+v", len(actions), actions)
+	}
+	for _, action := range actions {
+		if action.Kind != workspace.PruneUnknown {
+			t.Errorf("%s: %s (%s), want unknown without a token", action.Path, action.Kind, action.Reason)
+		}
+		if err := workspace.ApplyPrune(context.Background(), cfg, man, action); err != nil {
+			t.Fatalf("ApplyPrune: %v", err)
+		}
+		if _, err := os.Stat(action.Path); err != nil {
+			t.Errorf("%s was moved: %v", action.Path, err)
+		}
+	}
+
+	cfg.Token = "secret-token"
+	actions = reconcile(t, cfg)
+	if len(actions) != 1 {
+		t.Fatalf("got %d actions with a token, want 1: %+v", len(actions), actions)
+	}
+	if want := filepath.Join(cfg.BaseDir, "octocat", "gone"); actions[0].Kind != workspace.PruneArchive || actions[0].Path != want {
+		t.Errorf("action = %s %s, want archive %s", actions[0].Kind, actions[0].Path, want)
+	}
+}
+
+func TestReconcileSkipsGists(t *testing.T) {
+	server, cfg := clonePrune(t, &models.Repository{Name: "hello"})
+
+	gist := (&models.Gist{ID: "aa5a315d61ae9438b18d", Description: "Hello", Owner: models.Owner{Login: "octocat"}}).Repository()
+	gist.CloneURL = githubtest.BareRepo(t, t.TempDir(), gist.Name)
+	if err := cloner.NewManager(cfg).CloneRepositories([]*models.Repository{gist}, "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+	server.AddToken("secret-token", "octocat")
+	cfg.Token = "secret-token"
+
+	if actions := reconcile(t, cfg); len(actions) != 0 {
+		t.Errorf("got %d actions, want none for the gist: %+v", len(actions), actions)
+	}
+}
+
+func TestDiscoverSkipsWikis(t *testing.T) {
+	remotes := t.TempDir()
+	repo := &models.Repository{ID: 1, Name: "alpha", FullName: "octocat/alpha", HasWiki: true, CloneURL: githubtest.BareRepo(t, remotes, "alpha")}
+	githubtest.BareRepo(t, remotes, "alpha.wiki")
+
+	cfg := config.DefaultConfig()
+	cfg.BaseDir = t.TempDir()
+	cfg.CloneWikis = true
+	if err := cloner.NewManager(cfg).CloneRepositories([]*models.Repository{repo}, "octocat"); err != nil {
+		t.Fatalf("CloneRepositories: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.BaseDir, "octocat", "alpha.wiki")); err != nil {
+		t.Fatalf("wiki was not cloned: %v", err)
+	}
+
+	paths, err := workspace.Discover(cfg.BaseDir)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if want := []string{filepath.Join(cfg.BaseDir, "octocat", "alpha")}; !slices.Equal(paths, want) {
+		t.Errorf("Discover = %q, want %q", paths, want)
+	}
+}
@@ -0,0 +1,165 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/github"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Status inspects every working copy below root. When client is nil the
+// upstream existence check is skipped and reported as unknown.
+func Status(ctx context.Context, cfg *config.Config, client *github.Client, root string) ([]*models.RepoStatus, error) {
+	paths, err := Discover(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	statuses := make([]*models.RepoStatus, len(paths))
+	forEachPath(cfg.Concurrency, paths, func(i int, path string) {
+		statuses[i] = repoStatus(ctx, cfg, client, path)
+	})
+
+	return statuses, nil
+}
+
+// forEachPath runs fn on up to workers goroutines.
+func forEachPath(workers int, paths []string, fn func(int, string)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i, paths[i])
+			}
+		}()
+	}
+
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func repoStatus(ctx context.Context, cfg *config.Config, client *github.Client, path string) *models.RepoStatus {
+	ctx, cancel := context.WithTimeout(ctx, cfg.APITimeout)
+	defer cancel()
+
+	status := &models.RepoStatus{
+		Path:        path,
+		RemoteState: models.RemoteUnknown,
+	}
+
+	branch, err := git(ctx, path, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Branch = branch
+
+	if upstream, err := git(ctx, path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		status.Upstream = upstream
+		if counts, err := git(ctx, path, "rev-list", "--left-right", "--count", "HEAD...@{upstream}"); err == nil {
+			if fields := strings.Fields(counts); len(fields) == 2 {
+				status.Ahead, _ = strconv.Atoi(fields[0])
+				status.Behind, _ = strconv.Atoi(fields[1])
+			}
+		}
+	}
+
+	if porcelain, err := git(ctx, path, "status", "--porcelain"); err == nil {
+		status.DirtyFiles = countLines(porcelain)
+	}
+
+	if stashes, err := git(ctx, path, "stash", "list"); err == nil {
+		status.Stashes = countLines(stashes)
+	}
+
+	if remote, err := git(ctx, path, "remote", "get-url", "origin"); err == nil {
+		status.Remote = remote
+		if client != nil {
+			status.RemoteState = remoteState(client, remote)
+		}
+	}
+
+	return status
+}
+
+// remoteState asks GitHub whether the origin repository still exists. Remotes
+// on other hosts are reported as unknown.
+func remoteState(client *github.Client, remote string) string {
+	fullName, ok := GitHubFullName(remote)
+	if !ok {
+		return models.RemoteUnknown
+	}
+
+	exists, err := client.RepositoryExists(fullName)
+	switch {
+	case err != nil:
+		return models.RemoteUnknown
+	case exists:
+		return models.RemoteExists
+	default:
+		return models.RemoteMissing
+	}
+}
+
+// GitHubFullName extracts owner/name from an https or ssh github.com remote.
+func GitHubFullName(remote string) (string, bool) {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, path = u.Hostname(), strings.TrimPrefix(u.Path, "/")
+	} else if at := strings.Index(remote, "@"); at >= 0 {
+		hostPath := strings.SplitN(remote[at+1:], ":", 2)
+		if len(hostPath) != 2 {
+			return "", false
+		}
+		host, path = hostPath[0], hostPath[1]
+	}
+
+	if !strings.EqualFold(host, "github.com") || strings.Count(path, "/") != 1 {
+		return "", false
+	}
+	return path, true
+}
+
+func git(ctx context.Context, path string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", path}, args...)...)
+	cmd.Env = gitEnv()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitEnv keeps git from waiting on a credentials prompt for a fetch nobody
+// is there to answer.
+func gitEnv() []string {
+	return append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+}
+
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
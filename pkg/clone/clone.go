@@ -0,0 +1,38 @@
+// Package clone defines the extension points of the cloner: where
+// repositories come from, what runs git, and the events published as a run
+// progresses. Programs
+// embedding the cloner through package gclone can supply their own
+// implementations of each.
+package clone
+
+import (
+	"context"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Source lists the repositories belonging to an owner. The GitHub API
+// client is the default implementation.
+type Source interface {
+	Repositories(ctx context.Context, owner string) ([]*models.Repository, error)
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func(ctx context.Context, owner string) ([]*models.Repository, error)
+
+func (f SourceFunc) Repositories(ctx context.Context, owner string) ([]*models.Repository, error) {
+	return f(ctx, owner)
+}
+
+// Executor performs the git operations for a single working copy. The
+// cloner owns the surrounding bookkeeping (timeouts, target directories,
+// cleanup after a failed clone), so executors only talk to git.
+type Executor interface {
+	// Check reports whether the executor can run, e.g. that git is installed.
+	Check() error
+	Clone(ctx context.Context, repo *models.Repository, path string, onProgress func(models.TransferProgress)) error
+	Update(ctx context.Context, path string) error
+	Head(ctx context.Context, path string) (string, error)
+	// Remote returns the URL of the working copy's origin remote.
+	Remote(ctx context.Context, path string) (string, error)
+}
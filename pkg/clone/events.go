@@ -0,0 +1,97 @@
+package clone
+
+import (
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Event is something that happened during a clone run. The concrete types
+// below are the complete set; switch on them in a Subscriber.
+type Event interface {
+	event()
+}
+
+// RunStarted opens a run of Total repositories.
+type RunStarted struct {
+	Total int
+}
+
+// RepoQueued is published for every repository of a run before any work
+// starts.
+type RepoQueued struct {
+	Repository *models.Repository
+}
+
+// RepoStarted is published when a worker picks up a repository.
+type RepoStarted struct {
+	Repository *models.Repository
+}
+
+// PhaseChanged reports a repository entering a new transfer phase or
+// advancing within it.
+type PhaseChanged struct {
+	Repository *models.Repository
+	Phase      models.ClonePhase
+	Percent    int
+}
+
+// BytesReceived reports pack data received while in the receiving phase.
+type BytesReceived struct {
+	Repository  *models.Repository
+	Bytes       int64
+	BytesPerSec float64
+	Percent     int
+}
+
+// RepoSucceeded is published once a repository is cloned or updated.
+type RepoSucceeded struct {
+	Repository *models.Repository
+	Duration   time.Duration
+	Attempts   int
+}
+
+// RepoFailed is published once a repository has failed its last attempt.
+type RepoFailed struct {
+	Repository *models.Repository
+	Err        error
+	Duration   time.Duration
+	Attempts   int
+}
+
+// RunFinished closes a run. Cancelled is set when it was stopped early.
+type RunFinished struct {
+	Succeeded int
+	Failed    int
+	Duration  time.Duration
+	Cancelled bool
+}
+
+// Message is a human-readable note, e.g. why a repository was skipped.
+type Message struct {
+	Text string
+}
+
+func (RunStarted) event()    {}
+func (RepoQueued) event()    {}
+func (RepoStarted) event()   {}
+func (PhaseChanged) event()  {}
+func (BytesReceived) event() {}
+func (RepoSucceeded) event() {}
+func (RepoFailed) event()    {}
+func (RunFinished) event()   {}
+func (Message) event()       {}
+
+// Subscriber receives every event of a run. The cloner delivers events to
+// each subscriber one at a time and in order, so implementations don't need
+// their own locking for state only touched in Handle.
+type Subscriber interface {
+	Handle(event Event)
+}
+
+// SubscriberFunc adapts a function to a Subscriber.
+type SubscriberFunc func(Event)
+
+func (f SubscriberFunc) Handle(event Event) {
+	f(event)
+}
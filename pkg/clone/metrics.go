@@ -0,0 +1,68 @@
+package clone
+
+import (
+	"sync"
+	"time"
+)
+
+// MetricsSnapshot is a point-in-time copy of Metrics.
+type MetricsSnapshot struct {
+	Queued        int
+	Started       int
+	Succeeded     int
+	Failed        int
+	Retries       int
+	BytesReceived int64
+	// CloneTime is the summed duration of finished repositories, which
+	// exceeds wall time when clones run in parallel.
+	CloneTime time.Duration
+	Duration  time.Duration
+}
+
+// Metrics is a Subscriber that aggregates runs into counters, e.g. for
+// exit codes or exporting to a monitoring system. It is safe to read while
+// a run is in progress.
+type Metrics struct {
+	mu       sync.Mutex
+	snapshot MetricsSnapshot
+	bytes    map[int64]int64
+}
+
+func (m *Metrics) Handle(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.bytes == nil {
+		m.bytes = make(map[int64]int64)
+	}
+
+	switch e := event.(type) {
+	case RepoQueued:
+		m.snapshot.Queued++
+	case RepoStarted:
+		m.snapshot.Started++
+	case BytesReceived:
+		m.bytes[e.Repository.ID] = e.Bytes
+	case RepoSucceeded:
+		m.snapshot.Succeeded++
+		m.snapshot.Retries += e.Attempts - 1
+		m.snapshot.CloneTime += e.Duration
+	case RepoFailed:
+		m.snapshot.Failed++
+		m.snapshot.Retries += e.Attempts - 1
+		m.snapshot.CloneTime += e.Duration
+	case RunFinished:
+		m.snapshot.Duration += e.Duration
+	}
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := m.snapshot
+	for _, n := range m.bytes {
+		snapshot.BytesReceived += n
+	}
+	return snapshot
+}
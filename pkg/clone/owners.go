@@ -0,0 +1,82 @@
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// maxConcurrentListings bounds how many owners are listed at once.
+const maxConcurrentListings = 4
+
+// Listing is one owner's repositories in a run covering several owners.
+type Listing struct {
+	Owner        string
+	Repositories []*models.Repository
+	Err          error
+}
+
+// ListOwners lists every owner's repositories from source concurrently.
+// Listings are returned in the order of owners, each with its own error.
+func ListOwners(ctx context.Context, source Source, owners []string) []Listing {
+	listings := make([]Listing, len(owners))
+	sem := make(chan struct{}, maxConcurrentListings)
+	var wg sync.WaitGroup
+
+	for i, owner := range owners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			repos, err := source.Repositories(ctx, owner)
+			listings[i] = Listing{Owner: owner, Repositories: repos, Err: err}
+		}()
+	}
+
+	wg.Wait()
+	return listings
+}
+
+// Merge combines listings into one run, setting each repository's
+// RequestedOwner so layouts place it under the owner it was listed for. A
+// repository listed for several owners is kept for the first. The error
+// joins the failed listings; the repositories of the others are still
+// returned.
+func Merge(listings []Listing) ([]*models.Repository, error) {
+	var repos []*models.Repository
+	var errs []error
+	seen := make(map[int64]bool)
+
+	for _, listing := range listings {
+		if listing.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", listing.Owner, listing.Err))
+			continue
+		}
+		for _, repo := range listing.Repositories {
+			if seen[repo.ID] {
+				continue
+			}
+			seen[repo.ID] = true
+			repo.RequestedOwner = listing.Owner
+			repos = append(repos, repo)
+		}
+	}
+
+	return repos, errors.Join(errs...)
+}
+
+// AllFailed reports whether no owner could be listed. A run carries on with
+// the owners that were listed as long as there is at least one.
+func AllFailed(listings []Listing) bool {
+	for _, listing := range listings {
+		if listing.Err == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+// Package gclone is the library API of mass-git-cloner: list an owner's
+// repositories and clone them into a directory layout, without any terminal
+// output unless a Subscriber prints the run's events.
+//
+//	c, err := gclone.New(gclone.Options{BaseDir: "src"})
+//	repos, err := c.Repositories(ctx, "octocat")
+//	results, err := c.Clone(ctx, repos, "octocat")
+package gclone
+
+import (
+	"context"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/cloner"
+	"github.com/chetanr25/mass-git-cloner/internal/config"
+	"github.com/chetanr25/mass-git-cloner/internal/layout"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+// Options configures a Cloner. Zero values fall back to the same defaults
+// as the gclone command.
+type Options struct {
+	// BaseDir is the directory repositories are cloned into.
+	BaseDir string
+	// Layout is a preset (default, ghq, owner, language) or a template such
+	// as "{{.Owner}}/{{.Name}}".
+	Layout string
+	// Concurrency is the number of repositories cloned at once.
+	Concurrency int
+	// UpdateExisting pulls existing working copies instead of skipping them.
+	UpdateExisting bool
+	// Backend is "exec" or "go-git"; ignored when Executor is set.
+	Backend string
+	Depth   int
+	Branch  string
+	// Wikis also clones each repository's wiki into <repo>.wiki.
+	Wikis bool
+	// Token authenticates API requests and clones. Unlike the command, the
+	// library does not read GITHUB_TOKEN by itself.
+	Token string
+	// APIBaseURL points the default source at a GitHub Enterprise server or
+	// a test fake.
+	APIBaseURL   string
+	CloneTimeout time.Duration
+	// MaxAttempts counts tries per repository including retries after
+	// transient failures; RetryBackoff is the first delay between them,
+	// doubled after each retry.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// DiskCheck is "abort", "warn" or "off".
+	DiskCheck string
+
+	Source      clone.Source
+	Executor    clone.Executor
+	Subscribers []clone.Subscriber
+}
+
+// Cloner enumerates and clones repositories.
+type Cloner struct {
+	manager *cloner.Manager
+}
+
+// New validates opts and returns a Cloner.
+func New(opts Options) (*Cloner, error) {
+	cfg := config.DefaultConfig()
+	cfg.Token = opts.Token
+
+	if opts.BaseDir != "" {
+		cfg.BaseDir = opts.BaseDir
+	}
+	if opts.Layout != "" {
+		cfg.Layout = opts.Layout
+	}
+	if opts.Concurrency > 0 {
+		cfg.Concurrency = opts.Concurrency
+	}
+	if opts.Backend != "" {
+		cfg.Backend = opts.Backend
+	}
+	if opts.APIBaseURL != "" {
+		cfg.APIBaseURL = opts.APIBaseURL
+	}
+	if opts.CloneTimeout > 0 {
+		cfg.CloneTimeout = opts.CloneTimeout
+	}
+	if opts.MaxAttempts > 0 {
+		cfg.MaxAttempts = opts.MaxAttempts
+	}
+	if opts.RetryBackoff > 0 {
+		cfg.RetryBackoff = opts.RetryBackoff
+	}
+	if opts.DiskCheck != "" {
+		cfg.DiskCheck = opts.DiskCheck
+	}
+	cfg.UpdateExisting = opts.UpdateExisting
+	cfg.CloneDepth = opts.Depth
+	cfg.CloneBranch = opts.Branch
+	cfg.CloneWikis = opts.Wikis
+
+	if _, err := layout.Parse(cfg.Layout); err != nil {
+		return nil, err
+	}
+
+	var managerOpts []cloner.Option
+	if opts.Executor != nil {
+		managerOpts = append(managerOpts, cloner.WithExecutor(opts.Executor))
+	} else if _, err := cloner.NewBackend(cfg); err != nil {
+		return nil, err
+	}
+	if opts.Source != nil {
+		managerOpts = append(managerOpts, cloner.WithSource(opts.Source))
+	}
+	for _, subscriber := range opts.Subscribers {
+		managerOpts = append(managerOpts, cloner.WithSubscriber(subscriber))
+	}
+
+	return &Cloner{manager: cloner.NewManager(cfg, managerOpts...)}, nil
+}
+
+// Repositories lists owner's repositories from the configured source.
+func (c *Cloner) Repositories(ctx context.Context, owner string) ([]*models.Repository, error) {
+	return c.manager.Repositories(ctx, owner)
+}
+
+// RepositoriesOf lists several owners concurrently. Each repository is
+// tagged with the owner it was listed for, so Clone places it in that
+// owner's directory. When some owners fail, the error joins their failures
+// and the repositories of the others are still returned.
+func (c *Cloner) RepositoriesOf(ctx context.Context, owners ...string) ([]*models.Repository, error) {
+	return clone.Merge(clone.ListOwners(ctx, c.manager, owners))
+}
+
+// Plan reports what Clone would do with each repository without touching
+// the disk.
+func (c *Cloner) Plan(repos []*models.Repository, owner string) ([]*models.PlannedAction, error) {
+	return c.manager.Plan(repos, owner)
+}
+
+// Clone clones or updates repos for owner until ctx is cancelled. owner is
+// ignored for repositories from RepositoriesOf. The error is for problems
+// with the run as a whole; per-repository failures are reported in the
+// results.
+func (c *Cloner) Clone(ctx context.Context, repos []*models.Repository, owner string) ([]*models.CloneResult, error) {
+	if err := c.manager.CloneRepositoriesContext(ctx, repos, owner); err != nil {
+		return nil, err
+	}
+	return c.manager.Results(), nil
+}
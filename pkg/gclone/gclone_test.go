@@ -0,0 +1,233 @@
+package gclone_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chetanr25/mass-git-cloner/internal/githubtest"
+	"github.com/chetanr25/mass-git-cloner/pkg/clone"
+	"github.com/chetanr25/mass-git-cloner/pkg/gclone"
+	"github.com/chetanr25/mass-git-cloner/pkg/models"
+)
+
+type staticSource []*models.Repository
+
+func (s staticSource) Repositories(ctx context.Context, owner string) ([]*models.Repository, error) {
+	return s, nil
+}
+
+// fakeExecutor "clones" by creating a .git directory, failing for names in
+// fail. Names in flaky fail with a transient error that many times first.
+type fakeExecutor struct {
+	fail  map[string]bool
+	flaky map[string]int
+}
+
+func (e *fakeExecutor) Check() error { return nil }
+
+func (e *fakeExecutor) Clone(ctx context.Context, repo *models.Repository, path string, onProgress func(models.TransferProgress)) error {
+	if e.fail[repo.Name] {
+		return errors.New("repository not found")
+	}
+	if e.flaky[repo.Name] > 0 {
+		e.flaky[repo.Name]--
+		return errors.New("fatal: the remote end hung up unexpectedly")
+	}
+	onProgress(models.TransferProgress{Phase: models.PhaseDone, Percent: 100})
+	return os.MkdirAll(filepath.Join(path, ".git"), 0755)
+}
+
+func (e *fakeExecutor) Update(ctx context.Context, path string) error { return nil }
+
+func (e *fakeExecutor) Head(ctx context.Context, path string) (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (e *fakeExecutor) Remote(ctx context.Context, path string) (string, error) {
+	return "", errors.New("no origin remote")
+}
+
+type recordingSubscriber struct {
+	events []clone.Event
+}
+
+func (r *recordingSubscriber) Handle(event clone.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestCloneWithCustomSourceAndExecutor(t *testing.T) {
+	source := staticSource{
+		{ID: 1, Name: "alpha", FullName: "octocat/alpha"},
+		{ID: 2, Name: "beta", FullName: "octocat/beta"},
+	}
+	recorder := &recordingSubscriber{}
+	metrics := &clone.Metrics{}
+
+	c, err := gclone.New(gclone.Options{
+		BaseDir:     t.TempDir(),
+		Layout:      "owner",
+		Source:      source,
+		Executor:    &fakeExecutor{fail: map[string]bool{"beta": true}},
+		Subscribers: []clone.Subscriber{recorder, metrics},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	repos, err := c.Repositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Repositories: %v", err)
+	}
+
+	results, err := c.Clone(context.Background(), repos, "octocat")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, result := range results {
+		if want := result.Repository.Name == "alpha"; result.Success != want {
+			t.Errorf("%s: success = %v, want %v (err %v)", result.Repository.Name, result.Success, want, result.Error)
+		}
+	}
+
+	for _, event := range recorder.events {
+		if _, ok := event.(clone.Message); ok {
+			continue
+		}
+		if _, ok := event.(clone.RunStarted); !ok {
+			t.Errorf("first run event = %T, want clone.RunStarted", event)
+		}
+		break
+	}
+	if _, ok := recorder.events[len(recorder.events)-1].(clone.RunFinished); !ok {
+		t.Errorf("last event = %T, want clone.RunFinished", recorder.events[len(recorder.events)-1])
+	}
+
+	var failed *clone.RepoFailed
+	for _, event := range recorder.events {
+		if e, ok := event.(clone.RepoFailed); ok {
+			failed = &e
+		}
+	}
+	if failed == nil || failed.Repository.Name != "beta" {
+		t.Errorf("no RepoFailed event for beta")
+	}
+
+	snapshot := metrics.Snapshot()
+	if snapshot.Queued != 2 || snapshot.Succeeded != 1 || snapshot.Failed != 1 {
+		t.Errorf("metrics = %+v, want 2 queued, 1 succeeded, 1 failed", snapshot)
+	}
+}
+
+func TestCloneFromFakeGitHub(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{
+		Name:     "hello-world",
+		CloneURL: githubtest.BareRepo(t, t.TempDir(), "hello-world"),
+	})
+
+	baseDir := t.TempDir()
+	c, err := gclone.New(gclone.Options{BaseDir: baseDir, APIBaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	repos, err := c.Repositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Repositories: %v", err)
+	}
+
+	results, err := c.Clone(context.Background(), repos, "octocat")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("results = %+v", results)
+	}
+
+	if _, err := os.Stat(filepath.Join(baseDir, "octocat", "hello-world", "README.md")); err != nil {
+		t.Errorf("hello-world was not checked out: %v", err)
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	if _, err := gclone.New(gclone.Options{Backend: "svn"}); err == nil {
+		t.Error("New accepted an unknown backend")
+	}
+	if _, err := gclone.New(gclone.Options{Layout: "{{.Nope"}); err == nil {
+		t.Error("New accepted an invalid layout")
+	}
+}
+
+func TestCloneRetriesWithBackoff(t *testing.T) {
+	c, err := gclone.New(gclone.Options{
+		BaseDir:      t.TempDir(),
+		Source:       staticSource{{ID: 1, Name: "alpha", FullName: "octocat/alpha"}},
+		Executor:     &fakeExecutor{flaky: map[string]int{"alpha": 2}},
+		MaxAttempts:  3,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	repos, err := c.Repositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("Repositories: %v", err)
+	}
+	start := time.Now()
+	results, err := c.Clone(context.Background(), repos, "octocat")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success || results[0].Attempts != 3 {
+		t.Fatalf("results = %+v, want alpha cloned on the third attempt", results)
+	}
+	// The default backoff would wait 2s and then 4s.
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("clone took %s, want RetryBackoff to shorten the waits", elapsed)
+	}
+}
+
+func TestCloneSeveralOwners(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddRepos("octocat", &models.Repository{Name: "hello-world"}, &models.Repository{Name: "tools"})
+	server.AddOrg("acme")
+	server.AddRepos("acme", &models.Repository{Name: "tools"})
+
+	baseDir := t.TempDir()
+	c, err := gclone.New(gclone.Options{BaseDir: baseDir, APIBaseURL: server.URL, Executor: &fakeExecutor{}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	repos, err := c.RepositoriesOf(context.Background(), "octocat", "ghost", "acme")
+	if err == nil || !strings.Contains(err.Error(), "ghost") {
+		t.Errorf("RepositoriesOf error = %v, want one naming ghost", err)
+	}
+	if len(repos) != 3 {
+		t.Fatalf("got %d repositories, want the 3 of octocat and acme", len(repos))
+	}
+
+	results, err := c.Clone(context.Background(), repos, "")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	for _, path := range []string{"octocat/hello-world", "octocat/tools", "acme/tools"} {
+		if _, err := os.Stat(filepath.Join(baseDir, path, ".git")); err != nil {
+			t.Errorf("%s was not cloned into its owner's directory: %v", path, err)
+		}
+	}
+}
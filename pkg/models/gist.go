@@ -0,0 +1,95 @@
+package models
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Gist is a GitHub gist as returned by the gists API.
+type Gist struct {
+	ID          string              `json:"id"`
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]GistFile `json:"files"`
+	GitPullURL  string              `json:"git_pull_url"`
+	HTMLURL     string              `json:"html_url"`
+	Owner       Owner               `json:"owner"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+type GistFile struct {
+	Filename string `json:"filename"`
+	Language string `json:"language"`
+	Size     int    `json:"size"`
+}
+
+// FileNames returns the gist's file names in alphabetical order.
+func (g *Gist) FileNames() []string {
+	names := make([]string, 0, len(g.Files))
+	for name := range g.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Slug is the gist's directory name: its ID plus a slugified description.
+func (g *Gist) Slug() string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(g.Description) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 40 {
+			break
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if slug == "" {
+		return g.ID
+	}
+	return g.ID + "-" + slug
+}
+
+// Repository wraps the gist so it can be selected, laid out and cloned like
+// any repository. The ID is derived from the gist's hex ID so it stays
+// stable across runs.
+func (g *Gist) Repository() *Repository {
+	h := fnv.New64a()
+	h.Write([]byte("gist:" + g.ID))
+
+	size, language := 0, ""
+	for _, name := range g.FileNames() {
+		file := g.Files[name]
+		size += file.Size
+		if language == "" {
+			language = file.Language
+		}
+	}
+
+	return &Repository{
+		ID:          int64(h.Sum64() >> 1),
+		Name:        g.Slug(),
+		FullName:    g.Owner.Login + "/gists/" + g.Slug(),
+		Owner:       g.Owner,
+		Description: g.Description,
+		CloneURL:    g.GitPullURL,
+		Language:    language,
+		IsPrivate:   !g.Public,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+		PushedAt:    g.UpdatedAt,
+		Size:        (size + 1023) / 1024,
+		Gist:        g,
+	}
+}
@@ -1,25 +1,88 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Repository represents a GitHub repository
 type Repository struct {
-	ID            int64     `json:"id"`
-	Name          string    `json:"name"`
-	FullName      string    `json:"full_name"`
-	Description   string    `json:"description"`
-	CloneURL      string    `json:"clone_url"`
-	SSHURL        string    `json:"ssh_url"`
-	Language      string    `json:"language"`
-	StarCount     int       `json:"stargazers_count"`
-	ForkCount     int       `json:"forks_count"`
-	IsFork        bool      `json:"fork"`
-	IsPrivate     bool      `json:"private"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	Size          int       `json:"size"`
-	DefaultBranch string    `json:"default_branch"`
-	Selected      bool      `json:"-"`
+	ID            int64       `json:"id"`
+	Name          string      `json:"name"`
+	FullName      string      `json:"full_name"`
+	Owner         Owner       `json:"owner"`
+	Description   string      `json:"description"`
+	CloneURL      string      `json:"clone_url"`
+	SSHURL        string      `json:"ssh_url"`
+	Language      string      `json:"language"`
+	StarCount     int         `json:"stargazers_count"`
+	ForkCount     int         `json:"forks_count"`
+	IsFork        bool        `json:"fork"`
+	IsPrivate     bool        `json:"private"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	PushedAt      time.Time   `json:"pushed_at"`
+	Size          int         `json:"size"`
+	DefaultBranch string      `json:"default_branch"`
+	HasWiki       bool        `json:"has_wiki"`
+	Topics        []string    `json:"topics"`
+	License       *License    `json:"license"`
+	Parent        *Repository `json:"parent"`
+	// Languages, LanguageTotal, LatestRelease and HeadSHA are only filled in
+	// when the list came from the GraphQL API. Languages holds the largest
+	// ten and LanguageTotal the size of all of them. HeadSHA is the default
+	// branch's commit.
+	Languages     []LanguageSize `json:"-"`
+	LanguageTotal int            `json:"-"`
+	LatestRelease *Release       `json:"-"`
+	HeadSHA       string         `json:"-"`
+	// StarredAt is set for repositories listed from a user's stars.
+	StarredAt time.Time `json:"-"`
+	// Gist is set when the repository is a gist.
+	Gist *Gist `json:"-"`
+	// RequestedOwner is the owner whose listing returned the repository in
+	// a run covering several owners; layouts use it as .User.
+	RequestedOwner string `json:"-"`
+	Selected       bool   `json:"-"`
+}
+
+// DisplayName prefers owner/name so log lines stay unambiguous.
+func (r *Repository) DisplayName() string {
+	if r.FullName != "" {
+		return r.FullName
+	}
+	return r.Name
+}
+
+type Owner struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// LanguageSize is how much of a repository, in bytes, is in one language.
+type LanguageSize struct {
+	Name  string
+	Bytes int
+}
+
+type Release struct {
+	TagName     string
+	Name        string
+	PublishedAt time.Time
+}
+
+type License struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	SPDXID string `json:"spdx_id"`
+}
+
+// LocalRepoInfo describes what exists on disk for a repository's target path.
+type LocalRepoInfo struct {
+	Path         string
+	Exists       bool
+	IsGitRepo    bool
+	LastModified time.Time
 }
 
 type RepositoryStats struct {
@@ -28,6 +91,16 @@ type RepositoryStats struct {
 	NonForks int
 	Private  int
 	Public   int
+	// PerOwner breaks the totals down when several owners were listed.
+	PerOwner []*OwnerStats
+}
+
+// OwnerStats is one owner's share of a multi-owner listing. Err is set when
+// the owner couldn't be listed.
+type OwnerStats struct {
+	Owner string
+	Stats *RepositoryStats
+	Err   error
 }
 
 type FilterType int
@@ -36,6 +109,10 @@ const (
 	FilterAll FilterType = iota
 	FilterNonForks
 	FilterForksOnly
+	// FilterTeam and FilterTopic narrow an organization to one team's
+	// repositories or to one topic, fetched from GitHub when chosen.
+	FilterTeam
+	FilterTopic
 )
 
 func (f FilterType) String() string {
@@ -46,21 +123,177 @@ func (f FilterType) String() string {
 		return "Non-fork repositories only"
 	case FilterForksOnly:
 		return "Fork repositories only"
+	case FilterTeam:
+		return "Team repositories"
+	case FilterTopic:
+		return "Topic repositories"
 	default:
 		return "Unknown"
 	}
 }
 
+// ParseFilterType maps a command-line filter name to a FilterType.
+func ParseFilterType(name string) (FilterType, error) {
+	switch name {
+	case "all", "":
+		return FilterAll, nil
+	case "sources", "non-forks":
+		return FilterNonForks, nil
+	case "forks":
+		return FilterForksOnly, nil
+	default:
+		return FilterAll, fmt.Errorf("unknown filter %q (expected all, sources or forks)", name)
+	}
+}
+
+type SortField int
+
+const (
+	SortByUpdated SortField = iota
+	SortByName
+	SortByStars
+	SortByForks
+	SortBySize
+	SortByCreated
+	SortByLanguage
+)
+
+func (s SortField) String() string {
+	switch s {
+	case SortByUpdated:
+		return "updated"
+	case SortByName:
+		return "name"
+	case SortByStars:
+		return "stars"
+	case SortByForks:
+		return "forks"
+	case SortBySize:
+		return "size"
+	case SortByCreated:
+		return "created"
+	case SortByLanguage:
+		return "language"
+	default:
+		return "unknown"
+	}
+}
+
+// ClonePhase is the stage git reports while cloning a repository.
+type ClonePhase int
+
+const (
+	PhaseQueued ClonePhase = iota
+	PhaseCounting
+	PhaseCompressing
+	PhaseReceiving
+	PhaseResolving
+	PhaseCheckout
+	PhaseDone
+)
+
+func (p ClonePhase) String() string {
+	switch p {
+	case PhaseQueued:
+		return "queued"
+	case PhaseCounting:
+		return "counting objects"
+	case PhaseCompressing:
+		return "compressing objects"
+	case PhaseReceiving:
+		return "receiving objects"
+	case PhaseResolving:
+		return "resolving deltas"
+	case PhaseCheckout:
+		return "checking out files"
+	case PhaseDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// TransferProgress is one progress update parsed from git's output.
+type TransferProgress struct {
+	Phase         ClonePhase
+	Percent       int
+	BytesReceived int64
+	BytesPerSec   float64
+}
+
+// CloneAction is what a run will do with one repository.
+type CloneAction string
+
+const (
+	ActionClone      CloneAction = "clone"
+	ActionUpdate     CloneAction = "update"
+	ActionSkipExists CloneAction = "skip-exists"
+	ActionSkipNotGit CloneAction = "skip-not-git"
+	ActionConflict   CloneAction = "conflict"
+)
+
+// PlannedAction is one row of a dry-run plan.
+type PlannedAction struct {
+	Repository     *Repository `json:"-"`
+	Name           string      `json:"repository"`
+	Action         CloneAction `json:"action"`
+	Path           string      `json:"path"`
+	EstimatedBytes int64       `json:"estimated_bytes"`
+	Command        []string    `json:"command,omitempty"`
+	// Description says what will run when there is no command line, as
+	// with the go-git backend, which runs in-process.
+	Description string `json:"description,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// DiskSpaceReport compares the projected size of a run with the free space
+// on the filesystem holding the base directory. Required adds the safety
+// margin to Projected.
+type DiskSpaceReport struct {
+	Projected uint64 `json:"projected_bytes"`
+	Required  uint64 `json:"required_bytes"`
+	Available uint64 `json:"available_bytes"`
+}
+
+func (r *DiskSpaceReport) Sufficient() bool {
+	return r.Available >= r.Required
+}
+
+func (r *DiskSpaceReport) String() string {
+	return fmt.Sprintf("projected %.1f MB (%.1f MB with safety margin), %.1f MB free",
+		mb(r.Projected), mb(r.Required), mb(r.Available))
+}
+
+func mb(n uint64) float64 {
+	return float64(n) / (1024 * 1024)
+}
+
+// Upstream states reported by RepoStatus.RemoteState.
+const (
+	RemoteExists  = "exists"
+	RemoteMissing = "missing"
+	RemoteUnknown = "unknown"
+)
+
+// RepoStatus is the state of one local working copy.
+type RepoStatus struct {
+	Path        string `json:"path"`
+	Branch      string `json:"branch"`
+	Upstream    string `json:"upstream,omitempty"`
+	Ahead       int    `json:"ahead"`
+	Behind      int    `json:"behind"`
+	DirtyFiles  int    `json:"dirty_files"`
+	Stashes     int    `json:"stashes"`
+	Remote      string `json:"remote,omitempty"`
+	RemoteState string `json:"remote_state"`
+	Error       string `json:"error,omitempty"`
+}
+
 type CloneResult struct {
 	Repository *Repository
 	Success    bool
 	Error      error
 	Duration   time.Duration
-}
-
-type CloneProgress struct {
-	Total     int
-	Completed int
-	Failed    int
-	Current   string
+	// Attempts counts tries including retries after transient failures.
+	Attempts int
 }